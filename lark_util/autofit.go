@@ -0,0 +1,194 @@
+package lark_util
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+/** -------------------------------------------------自适应行高列宽----------------------------------------------------------- **/
+
+type AutoFitReq struct {
+	ExcelToken string
+	SheetId    string
+	StartCell  string          // Values写入的起始单元格,默认A1
+	Values     [][]interface{} // 写入的数据,用于估算宽度
+	FontSize   int             // 字体大小(磅),默认10
+	BoldRows   int             // 前几行为加粗的表头,估算时加宽
+	MinWidth   int             // 最小列宽(像素),默认40
+	MaxWidth   int             // 最大列宽(像素),默认400;超过时按换行估算行高
+	WrapText   bool            // 是否换行,为true时会为超过MaxWidth的内容增加行高
+}
+
+// AutoFit 根据写入的数据估算列宽(中日韩等全角字符按两倍宽度计算)和换行后的行高,
+// 连续且相同的宽度/高度合并为一次dimension_range更新;只有需要多行的行才会设置行高
+func (l *LarkU) AutoFit(req *AutoFitReq) (err error) {
+	widths, heights, err := EstimateSizes(req)
+	if err != nil {
+		return
+	}
+	if err = l.setDimensionSizes(req.ExcelToken, req.SheetId, MajorDimensionCols, widths); err != nil {
+		return
+	}
+	return l.setDimensionSizes(req.ExcelToken, req.SheetId, MajorDimensionRows, heights)
+}
+
+// EstimateSizes 估算AutoFit要设置的列宽和行高(像素),均按表格中从0开始的下标排列,0为不设置
+func EstimateSizes(req *AutoFitReq) (widths, heights []int, err error) {
+	if req.StartCell == "" {
+		req.StartCell = "A1"
+	}
+	startCol, startRow, err := ParseCellRef(req.StartCell)
+	if err != nil {
+		return
+	}
+	fontSize := float64(req.FontSize)
+	if fontSize <= 0 {
+		fontSize = 10
+	}
+	minWidth, maxWidth := req.MinWidth, req.MaxWidth
+	if minWidth <= 0 {
+		minWidth = 40
+	}
+	if maxWidth <= 0 {
+		maxWidth = 400
+	}
+	const (
+		padding     = 12 // 列宽的左右留白
+		cellPadding = 6  // 行高的上下留白
+	)
+	// 1磅约为4/3像素,半角字符宽度约为字号的0.55倍,全角字符为字号的1倍,行高为字号的1.5倍
+	charPx := fontSize * 4 / 3 * 0.55
+	lineHeight := fontSize * 4 / 3 * 1.5
+
+	units := make([][]float64, len(req.Values)) // 每个单元格的宽度单位,半角为1
+	for i, row := range req.Values {
+		units[i] = make([]float64, len(row))
+		for j, v := range row {
+			units[i][j] = displayWidth(autoFitText(v))
+			if i < req.BoldRows {
+				units[i][j] *= 1.1
+			}
+		}
+	}
+	for _, row := range units {
+		for j, u := range row {
+			if startCol+j >= len(widths) {
+				widths = append(widths, make([]int, startCol+j+1-len(widths))...)
+			}
+			w := int(math.Ceil(u*charPx)) + padding
+			if w < minWidth {
+				w = minWidth
+			}
+			if w > maxWidth {
+				w = maxWidth
+			}
+			if w > widths[startCol+j] {
+				widths[startCol+j] = w
+			}
+		}
+	}
+	for i, row := range units {
+		lines := 1
+		for j, u := range row {
+			text := autoFitText(req.Values[i][j])
+			n := strings.Count(text, "\n") + 1
+			if req.WrapText {
+				usable := float64(widths[startCol+j]-padding) / charPx
+				if usable > 0 && u > usable {
+					if wrapped := int(math.Ceil(u / usable)); wrapped > n {
+						n = wrapped
+					}
+				}
+			}
+			if n > lines {
+				lines = n
+			}
+		}
+		if lines > 1 {
+			if startRow+i >= len(heights) {
+				heights = append(heights, make([]int, startRow+i+1-len(heights))...)
+			}
+			heights[startRow+i] = int(math.Ceil(float64(lines)*lineHeight)) + cellPadding
+		}
+	}
+	return
+}
+
+// displayWidth 返回文本的显示宽度,半角字符为1,全角字符为2;多行文本取最宽的一行
+func displayWidth(s string) (width float64) {
+	for _, line := range strings.Split(s, "\n") {
+		w := 0.0
+		for _, r := range line {
+			if isWideRune(r) {
+				w += 2
+			} else {
+				w++
+			}
+		}
+		if w > width {
+			width = w
+		}
+	}
+	return
+}
+
+func isWideRune(r rune) bool {
+	if r < utf8.RuneSelf {
+		return false
+	}
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		(r >= 0x3000 && r <= 0x303F) || // 中日韩标点
+		(r >= 0xFF00 && r <= 0xFF60) || (r >= 0xFFE0 && r <= 0xFFE6) || // 全角字符
+		(r >= 0x1F300 && r <= 0x1FAFF) // emoji
+}
+
+// autoFitText 单元格显示的大致文本,公式的结果无法得知,不参与估算
+func autoFitText(v interface{}) string {
+	switch t := v.(type) {
+	case FormulaValue:
+		return ""
+	case LinkValue:
+		return t.Text
+	case MentionValue:
+		return "@" + t.Text
+	case DateValue:
+		return t.Time().Format("2006/01/02")
+	case int:
+		return strconv.Itoa(t)
+	case int64:
+		return strconv.FormatInt(t, 10)
+	}
+	return cellText(v)
+}
+
+// setDimensionSizes 按下标设置行高/列宽,连续且相同的大小合并为一次更新,0为不设置
+func (l *LarkU) setDimensionSizes(excelToken, sheetId, majorDimension string, sizes []int) (err error) {
+	for i := 0; i < len(sizes); {
+		j := i
+		for j < len(sizes) && sizes[j] == sizes[i] {
+			j++
+		}
+		if sizes[i] > 0 {
+			err = l.UpdateDimension(&UpdateDimensionReq{
+				ExcelToken: excelToken,
+				Dimension: &UpdateDimension{
+					SheetID:        sheetId,
+					MajorDimension: majorDimension,
+					StartIndex:     i + 1,
+					EndIndex:       j,
+				},
+				DimensionProperties: &UpdateDimensionProperties{FixedSize: sizes[i]},
+			})
+			if err != nil {
+				return
+			}
+		}
+		i = j
+	}
+	return
+}
+
+/** -------------------------------------------------自适应行高列宽----------------------------------------------------------- **/
@@ -2,20 +2,25 @@ package lark_util
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"time"
 )
 
 type LarkU struct {
-	appId     string
-	appSecret string
-	client    *http.Client
-	larkHost  string
-	larkToken string
+	appId       string
+	appSecret   string
+	client      *http.Client
+	fileClient  *http.Client // 与client共用连接池但不设超时,用于耗时不定的文件上传下载
+	larkHost    string
+	larkToken   string
+	infoCache   *ttlCache[string, *ExcelInfo]   // 表格元数据的短期缓存,见CachedExcelInfo
+	userIdCache *ttlCache[string, UserIdResult] // 邮箱、手机号到用户id的缓存,见ResolveUserIds
 }
 
 type LarkMeta struct {
@@ -43,6 +48,9 @@ func NewLarkU(meta *LarkMeta) *LarkU {
 		},
 		larkHost: "open.feishu.cn",
 	}
+	l.fileClient = &http.Client{Transport: l.client.Transport}
+	l.infoCache = newTTLCache[string, *ExcelInfo](30 * time.Second)
+	l.userIdCache = newTTLCache[string, UserIdResult](10 * time.Minute)
 	l.setLarkToken()
 	go func() {
 		for range time.NewTicker(time.Minute * 5).C {
@@ -143,6 +151,31 @@ func (l *LarkU) LarkPut(path string, param map[string]interface{}) (httpCode int
 	return
 }
 
+func (l *LarkU) LarkPatch(path string, param map[string]interface{}) (httpCode int, responseBody []byte, err error) {
+	b, _ := json.Marshal(param)
+	var bufferBody *bytes.Buffer
+	if b == nil || len(b) <= 0 {
+		bufferBody = bytes.NewBuffer([]byte("{}"))
+	} else {
+		bufferBody = bytes.NewBuffer(b)
+	}
+	req, err := http.NewRequest(http.MethodPatch, "https://"+l.larkHost+path, bufferBody)
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+l.larkToken)
+	resp, err := l.client.Do(req)
+	if err != nil {
+		httpCode = http.StatusInternalServerError
+		return
+	}
+	defer resp.Body.Close()
+	responseBody, err = ioutil.ReadAll(resp.Body)
+	httpCode = resp.StatusCode
+	return
+}
+
 func (l *LarkU) LarkDelete(path string, param map[string]interface{}) (httpCode int, responseBody []byte, err error) {
 	b, _ := json.Marshal(param)
 	var bufferBody *bytes.Buffer
@@ -166,3 +199,94 @@ func (l *LarkU) LarkDelete(path string, param map[string]interface{}) (httpCode
 	httpCode = resp.StatusCode
 	return
 }
+
+// LarkPostMultipart 以multipart/form-data上传文件,fields为普通表单字段,文件内容放在fileField字段中.
+// 与LarkDownload一样不受client的超时限制,由ctx控制取消
+func (l *LarkU) LarkPostMultipart(ctx context.Context, path string, fields map[string]string, fileField, fileName string, file io.Reader) (httpCode int, responseBody []byte, err error) {
+	bufferBody := new(bytes.Buffer)
+	mw := multipart.NewWriter(bufferBody)
+	for k, v := range fields {
+		if err = mw.WriteField(k, v); err != nil {
+			return
+		}
+	}
+	fw, err := mw.CreateFormFile(fileField, fileName)
+	if err != nil {
+		return
+	}
+	if _, err = io.Copy(fw, file); err != nil {
+		return
+	}
+	if err = mw.Close(); err != nil {
+		return
+	}
+	return l.LarkPostRaw(ctx, path, mw.FormDataContentType(), bufferBody)
+}
+
+// LarkPostRaw 以原始的请求体发送POST请求,contentType为请求体的类型.
+// 与LarkDownload一样不受client的超时限制,由ctx控制取消
+func (l *LarkU) LarkPostRaw(ctx context.Context, path, contentType string, body io.Reader) (httpCode int, responseBody []byte, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://"+l.larkHost+path, body)
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "Bearer "+l.larkToken)
+	resp, err := l.fileClient.Do(req)
+	if err != nil {
+		httpCode = http.StatusInternalServerError
+		return
+	}
+	defer resp.Body.Close()
+	responseBody, err = ioutil.ReadAll(resp.Body)
+	httpCode = resp.StatusCode
+	return
+}
+
+// LarkDownload 以流的形式把响应写入w,适用于文件下载.下载不受client的超时限制,由ctx控制取消;
+// 非200时不写入w,responseBody为错误响应体
+func (l *LarkU) LarkDownload(ctx context.Context, path string, form url.Values, w io.Writer) (httpCode int, responseBody []byte, err error) {
+	urlStr := "https://" + l.larkHost + path
+	if len(form) > 0 {
+		urlStr += "?" + form.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlStr, nil)
+	if err != nil {
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+l.larkToken)
+	resp, err := l.fileClient.Do(req)
+	if err != nil {
+		httpCode = http.StatusInternalServerError
+		return
+	}
+	defer resp.Body.Close()
+	httpCode = resp.StatusCode
+	if httpCode != http.StatusOK {
+		responseBody, err = ioutil.ReadAll(resp.Body)
+		return
+	}
+	_, err = io.Copy(w, resp.Body)
+	return
+}
+
+// pollUntil 轮询fn直到完成或出错,每次间隔翻倍直到maxInterval,ctx结束时返回ctx.Err()
+func pollUntil(ctx context.Context, interval, maxInterval time.Duration, fn func() (done bool, err error)) error {
+	for {
+		done, err := fn()
+		if err != nil || done {
+			return err
+		}
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		interval *= 2
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
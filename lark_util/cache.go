@@ -0,0 +1,61 @@
+package lark_util
+
+import (
+	"sync"
+	"time"
+)
+
+// ttlCache 带过期时间的并发安全缓存
+type ttlCache[K comparable, V any] struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	items map[K]ttlItem[V]
+}
+
+type ttlItem[V any] struct {
+	value    V
+	expireAt time.Time
+}
+
+func newTTLCache[K comparable, V any](ttl time.Duration) *ttlCache[K, V] {
+	return &ttlCache[K, V]{ttl: ttl, items: make(map[K]ttlItem[V])}
+}
+
+func (c *ttlCache[K, V]) Get(key K) (value V, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item, ok := c.items[key]
+	if !ok {
+		return
+	}
+	if time.Now().After(item.expireAt) {
+		delete(c.items, key)
+		return value, false
+	}
+	return item.value, true
+}
+
+func (c *ttlCache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.ttl)
+}
+
+func (c *ttlCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	c.mu.Lock()
+	c.items[key] = ttlItem[V]{value: value, expireAt: time.Now().Add(ttl)}
+	c.mu.Unlock()
+}
+
+func (c *ttlCache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	delete(c.items, key)
+	c.mu.Unlock()
+}
+
+// DeleteIf 缓存的值满足stale时删除
+func (c *ttlCache[K, V]) DeleteIf(key K, stale func(V) bool) {
+	c.mu.Lock()
+	if item, ok := c.items[key]; ok && stale(item.value) {
+		delete(c.items, key)
+	}
+	c.mu.Unlock()
+}
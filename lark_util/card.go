@@ -0,0 +1,479 @@
+package lark_util
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+/** -------------------------------------------------消息卡片-------------------------------------------------------------------- **/
+
+// 卡片标题栏颜色
+const (
+	CardTemplateBlue      = "blue"
+	CardTemplateWathet    = "wathet"
+	CardTemplateTurquoise = "turquoise"
+	CardTemplateGreen     = "green"
+	CardTemplateYellow    = "yellow"
+	CardTemplateOrange    = "orange"
+	CardTemplateRed       = "red"
+	CardTemplateCarmine   = "carmine"
+	CardTemplateViolet    = "violet"
+	CardTemplatePurple    = "purple"
+	CardTemplateIndigo    = "indigo"
+	CardTemplateGrey      = "grey"
+	CardTemplateDefault   = "default"
+)
+
+// 按钮样式
+const (
+	ButtonTypeDefault = "default"
+	ButtonTypePrimary = "primary"
+	ButtonTypeDanger  = "danger"
+)
+
+// 卡片的限制
+const (
+	maxCardSize       = 30 * 1024 // 卡片json的大小
+	maxCardElements   = 50        // 卡片的顶层元素数
+	maxCardActions    = 20        // 一个交互模块中的按钮、选择器数
+	maxCardColumns    = 5         // 一个分栏中的列数
+	maxCardColumnDeep = 1         // 分栏中不能再嵌套分栏
+)
+
+// CardElement 卡片中的元素,用CardMarkdown、CardDiv、CardLinkButton等函数创建
+type CardElement interface {
+	cardTag() string
+}
+
+type (
+	Card struct {
+		Config   *CardConfig   `json:"config,omitempty"`
+		Header   *CardHeader   `json:"header,omitempty"`
+		Elements []CardElement `json:"elements"`
+	}
+	CardConfig struct {
+		WideScreenMode bool `json:"wide_screen_mode"`
+		EnableForward  bool `json:"enable_forward"`
+		UpdateMulti    bool `json:"update_multi"` // 为true时更新卡片对所有人生效,PatchCard需要
+	}
+	CardHeader struct {
+		Title    *CardTextElement `json:"title"`
+		Template string           `json:"template,omitempty"` // 见CardTemplate开头的常量
+	}
+	// CardTextElement 文本,tag为plain_text或lark_md
+	CardTextElement struct {
+		Tag     string `json:"tag"`
+		Content string `json:"content"`
+	}
+	CardDivElement struct {
+		Tag    string           `json:"tag"`
+		Text   *CardTextElement `json:"text,omitempty"`
+		Fields []*CardField     `json:"fields,omitempty"`
+		Extra  CardElement      `json:"extra,omitempty"` // 显示在文本右侧的图片或按钮
+	}
+	// CardField 多列文本中的一个字段,IsShort为true时两个字段并排显示
+	CardField struct {
+		IsShort bool             `json:"is_short"`
+		Text    *CardTextElement `json:"text"`
+	}
+	CardMarkdownElement struct {
+		Tag       string `json:"tag"`
+		Content   string `json:"content"`
+		TextAlign string `json:"text_align,omitempty"` // left、center、right
+	}
+	CardHrElement struct {
+		Tag string `json:"tag"`
+	}
+	CardImageElement struct {
+		Tag    string           `json:"tag"`
+		ImgKey string           `json:"img_key"`
+		Alt    *CardTextElement `json:"alt"`
+		Title  *CardTextElement `json:"title,omitempty"`
+		Mode   string           `json:"mode,omitempty"` // fit_horizontal、crop_center
+	}
+	// CardNoteElement 备注,Elements只能是文本或图片
+	CardNoteElement struct {
+		Tag      string        `json:"tag"`
+		Elements []CardElement `json:"elements"`
+	}
+	// CardActionElement 交互模块,Actions为按钮、选择器或日期选择器
+	CardActionElement struct {
+		Tag     string        `json:"tag"`
+		Actions []CardElement `json:"actions"`
+		Layout  string        `json:"layout,omitempty"` // bisected、trisection、flow
+	}
+	CardButtonElement struct {
+		Tag     string                 `json:"tag"`
+		Text    *CardTextElement       `json:"text"`
+		Type    string                 `json:"type,omitempty"` // 见ButtonType开头的常量
+		Url     string                 `json:"url,omitempty"`
+		Value   map[string]interface{} `json:"value,omitempty"` // 点击后回调给应用的数据
+		Confirm *CardConfirm           `json:"confirm,omitempty"`
+	}
+	// CardConfirm 点击后弹出的二次确认
+	CardConfirm struct {
+		Title *CardTextElement `json:"title"`
+		Text  *CardTextElement `json:"text"`
+	}
+	CardSelectElement struct {
+		Tag           string                 `json:"tag"`
+		Placeholder   *CardTextElement       `json:"placeholder,omitempty"`
+		InitialOption string                 `json:"initial_option,omitempty"`
+		Options       []*CardOption          `json:"options"`
+		Value         map[string]interface{} `json:"value,omitempty"`
+	}
+	CardOption struct {
+		Text  *CardTextElement `json:"text"`
+		Value string           `json:"value"`
+	}
+	CardDatePickerElement struct {
+		Tag         string                 `json:"tag"`
+		Placeholder *CardTextElement       `json:"placeholder,omitempty"`
+		InitialDate string                 `json:"initial_date,omitempty"` // 2006-01-02
+		Value       map[string]interface{} `json:"value,omitempty"`
+	}
+	CardColumnSetElement struct {
+		Tag             string        `json:"tag"`
+		FlexMode        string        `json:"flex_mode"`                  // none、stretch、flow、bisect、trisect
+		BackgroundStyle string        `json:"background_style,omitempty"` // default、grey
+		Columns         []*CardColumn `json:"columns"`
+	}
+	CardColumn struct {
+		Tag           string        `json:"tag"`
+		Width         string        `json:"width,omitempty"`  // auto、weighted
+		Weight        int           `json:"weight,omitempty"` // width为weighted时的比例,1-5
+		VerticalAlign string        `json:"vertical_align,omitempty"`
+		Elements      []CardElement `json:"elements"`
+	}
+)
+
+func (e *CardTextElement) cardTag() string       { return e.Tag }
+func (e *CardDivElement) cardTag() string        { return e.Tag }
+func (e *CardMarkdownElement) cardTag() string   { return e.Tag }
+func (e *CardHrElement) cardTag() string         { return e.Tag }
+func (e *CardImageElement) cardTag() string      { return e.Tag }
+func (e *CardNoteElement) cardTag() string       { return e.Tag }
+func (e *CardActionElement) cardTag() string     { return e.Tag }
+func (e *CardButtonElement) cardTag() string     { return e.Tag }
+func (e *CardSelectElement) cardTag() string     { return e.Tag }
+func (e *CardDatePickerElement) cardTag() string { return e.Tag }
+func (e *CardColumnSetElement) cardTag() string  { return e.Tag }
+
+// MsgType 卡片可以直接作为SendMessageReq.Content发送
+func (c *Card) MsgType() string { return MsgTypeInteractive }
+
+func CardPlainText(content string) *CardTextElement {
+	return &CardTextElement{Tag: "plain_text", Content: content}
+}
+
+// CardLarkMd 支持部分markdown语法的文本,用于div、字段和备注
+func CardLarkMd(content string) *CardTextElement {
+	return &CardTextElement{Tag: "lark_md", Content: content}
+}
+
+func CardMarkdown(content string) *CardMarkdownElement {
+	return &CardMarkdownElement{Tag: "markdown", Content: content}
+}
+
+func CardDiv(text *CardTextElement, fields ...*CardField) *CardDivElement {
+	return &CardDivElement{Tag: "div", Text: text, Fields: fields}
+}
+
+// CardShortField 并排显示的字段,content为lark_md
+func CardShortField(content string) *CardField {
+	return &CardField{IsShort: true, Text: CardLarkMd(content)}
+}
+
+// CardLongField 独占一行的字段,content为lark_md
+func CardLongField(content string) *CardField {
+	return &CardField{Text: CardLarkMd(content)}
+}
+
+func CardHr() *CardHrElement {
+	return &CardHrElement{Tag: "hr"}
+}
+
+func CardImage(imgKey, alt string) *CardImageElement {
+	return &CardImageElement{Tag: "img", ImgKey: imgKey, Alt: CardPlainText(alt)}
+}
+
+func CardNote(elements ...CardElement) *CardNoteElement {
+	return &CardNoteElement{Tag: "note", Elements: elements}
+}
+
+func CardAction(actions ...CardElement) *CardActionElement {
+	return &CardActionElement{Tag: "action", Actions: actions}
+}
+
+// CardLinkButton 点击后打开链接的按钮,buttonType见ButtonType开头的常量
+func CardLinkButton(text, url, buttonType string) *CardButtonElement {
+	return &CardButtonElement{Tag: "button", Text: CardPlainText(text), Type: buttonType, Url: url}
+}
+
+// CardCallbackButton 点击后把value回调给应用的按钮
+func CardCallbackButton(text, buttonType string, value map[string]interface{}) *CardButtonElement {
+	return &CardButtonElement{Tag: "button", Text: CardPlainText(text), Type: buttonType, Value: value}
+}
+
+// CardSelect 下拉选择,选项用NewCardOption创建,选择后把value和选项的值回调给应用
+func CardSelect(placeholder string, value map[string]interface{}, options ...*CardOption) *CardSelectElement {
+	return &CardSelectElement{Tag: "select_static", Placeholder: CardPlainText(placeholder), Options: options, Value: value}
+}
+
+func NewCardOption(text, value string) *CardOption {
+	return &CardOption{Text: CardPlainText(text), Value: value}
+}
+
+// CardDatePicker 日期选择,initial为零值时不设初始日期
+func CardDatePicker(placeholder string, initial time.Time, value map[string]interface{}) *CardDatePickerElement {
+	e := &CardDatePickerElement{Tag: "date_picker", Placeholder: CardPlainText(placeholder), Value: value}
+	if !initial.IsZero() {
+		e.InitialDate = initial.Format("2006-01-02")
+	}
+	return e
+}
+
+func CardColumnSet(flexMode string, columns ...*CardColumn) *CardColumnSetElement {
+	return &CardColumnSetElement{Tag: "column_set", FlexMode: flexMode, Columns: columns}
+}
+
+// NewCardColumn 按weight比例分配宽度的列,weight为0时宽度自适应
+func NewCardColumn(weight int, elements ...CardElement) *CardColumn {
+	c := &CardColumn{Tag: "column", Width: "auto", Elements: elements}
+	if weight > 0 {
+		c.Width, c.Weight = "weighted", weight
+	}
+	return c
+}
+
+// CardBuilder 逐个添加元素构建卡片,Build时校验:
+//
+//	card, err := NewCardBuilder("巡检完成", CardTemplateGreen).
+//		Markdown("**失败** 0 项").
+//		Fields(CardShortField("**负责人**\n张三"), CardShortField("**耗时**\n3分钟")).
+//		Actions(CardLinkButton("查看报表", sheetUrl, ButtonTypePrimary)).
+//		Build()
+type CardBuilder struct {
+	card *Card
+}
+
+// NewCardBuilder title为空时卡片没有标题栏,template见CardTemplate开头的常量
+func NewCardBuilder(title, template string) *CardBuilder {
+	card := &Card{Config: &CardConfig{WideScreenMode: true, EnableForward: true}}
+	if title != "" {
+		card.Header = &CardHeader{Title: CardPlainText(title), Template: template}
+	}
+	return &CardBuilder{card: card}
+}
+
+// UpdateMulti 卡片更新后对所有接收者生效,之后需要用PatchCard更新的卡片要设置
+func (b *CardBuilder) UpdateMulti() *CardBuilder {
+	b.card.Config.UpdateMulti = true
+	return b
+}
+
+func (b *CardBuilder) Element(elements ...CardElement) *CardBuilder {
+	b.card.Elements = append(b.card.Elements, elements...)
+	return b
+}
+
+func (b *CardBuilder) Markdown(content string) *CardBuilder {
+	return b.Element(CardMarkdown(content))
+}
+
+func (b *CardBuilder) Text(content string) *CardBuilder {
+	return b.Element(CardDiv(CardPlainText(content)))
+}
+
+func (b *CardBuilder) Fields(fields ...*CardField) *CardBuilder {
+	return b.Element(CardDiv(nil, fields...))
+}
+
+func (b *CardBuilder) Image(imgKey, alt string) *CardBuilder {
+	return b.Element(CardImage(imgKey, alt))
+}
+
+func (b *CardBuilder) Hr() *CardBuilder {
+	return b.Element(CardHr())
+}
+
+// Note 添加一行备注,contents为lark_md
+func (b *CardBuilder) Note(contents ...string) *CardBuilder {
+	elements := make([]CardElement, len(contents))
+	for i, content := range contents {
+		elements[i] = CardLarkMd(content)
+	}
+	return b.Element(CardNote(elements...))
+}
+
+func (b *CardBuilder) Actions(actions ...CardElement) *CardBuilder {
+	return b.Element(CardAction(actions...))
+}
+
+func (b *CardBuilder) Columns(flexMode string, columns ...*CardColumn) *CardBuilder {
+	return b.Element(CardColumnSet(flexMode, columns...))
+}
+
+// Build 校验并返回卡片
+func (b *CardBuilder) Build() (card *Card, err error) {
+	if err = b.card.Validate(); err != nil {
+		return
+	}
+	return b.card, nil
+}
+
+// Validate 校验卡片的结构和大小限制
+func (c *Card) Validate() error {
+	if len(c.Elements) == 0 {
+		return errors.New("card has no elements")
+	}
+	if len(c.Elements) > maxCardElements {
+		return errors.Errorf("card has %d elements, at most %d", len(c.Elements), maxCardElements)
+	}
+	if c.Header != nil && (c.Header.Title == nil || c.Header.Title.Content == "") {
+		return errors.New("card header title is empty")
+	}
+	for i, e := range c.Elements {
+		if err := validateCardElement(e, 0); err != nil {
+			return errors.Wrapf(err, "element %d", i)
+		}
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return errors.Wrap(err, "marshal card")
+	}
+	if len(b) > maxCardSize {
+		return errors.Errorf("card is %d bytes, at most %d", len(b), maxCardSize)
+	}
+	return nil
+}
+
+// isNilCardElement 元素为nil接口或nil指针
+func isNilCardElement(e CardElement) bool {
+	if e == nil {
+		return true
+	}
+	v := reflect.ValueOf(e)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+func validateCardElement(e CardElement, columnDeep int) error {
+	if isNilCardElement(e) {
+		return errors.New("nil element")
+	}
+	switch e := e.(type) {
+	case *CardDivElement:
+		if e.Text == nil && len(e.Fields) == 0 {
+			return errors.New("div has neither text nor fields")
+		}
+		for i, f := range e.Fields {
+			if f == nil || f.Text == nil {
+				return errors.Errorf("div field %d has no text", i)
+			}
+		}
+		if e.Extra != nil {
+			if isNilCardElement(e.Extra) {
+				return errors.New("div extra is nil")
+			}
+			switch e.Extra.(type) {
+			case *CardImageElement, *CardButtonElement, *CardSelectElement, *CardDatePickerElement:
+			default:
+				return errors.Errorf("div extra can not be %T", e.Extra)
+			}
+			if err := validateCardElement(e.Extra, columnDeep); err != nil {
+				return errors.Wrap(err, "div extra")
+			}
+		}
+	case *CardImageElement:
+		if e.ImgKey == "" {
+			return errors.New("image has no img_key")
+		}
+	case *CardNoteElement:
+		if len(e.Elements) == 0 {
+			return errors.New("note has no elements")
+		}
+		for i, n := range e.Elements {
+			if isNilCardElement(n) {
+				return errors.Errorf("note element %d is nil", i)
+			}
+			switch n.(type) {
+			case *CardTextElement, *CardImageElement:
+			default:
+				return errors.Errorf("note can not contain %T", n)
+			}
+			if err := validateCardElement(n, columnDeep); err != nil {
+				return errors.Wrapf(err, "note element %d", i)
+			}
+		}
+	case *CardActionElement:
+		if len(e.Actions) == 0 || len(e.Actions) > maxCardActions {
+			return errors.Errorf("action has %d actions, should be 1-%d", len(e.Actions), maxCardActions)
+		}
+		for i, a := range e.Actions {
+			if isNilCardElement(a) {
+				return errors.Errorf("action %d is nil", i)
+			}
+			switch a.(type) {
+			case *CardButtonElement, *CardSelectElement, *CardDatePickerElement:
+			default:
+				return errors.Errorf("action %d can not be %T", i, a)
+			}
+			if err := validateCardElement(a, columnDeep); err != nil {
+				return errors.Wrapf(err, "action %d", i)
+			}
+		}
+	case *CardButtonElement:
+		if e.Text == nil || e.Text.Content == "" {
+			return errors.New("button text is empty")
+		}
+		if e.Url == "" && len(e.Value) == 0 {
+			return errors.Errorf("button %q has neither url nor value", e.Text.Content)
+		}
+	case *CardSelectElement:
+		if len(e.Options) == 0 {
+			return errors.New("select has no options")
+		}
+		found := e.InitialOption == ""
+		for i, o := range e.Options {
+			if o == nil {
+				return errors.Errorf("select option %d is nil", i)
+			}
+			found = found || o.Value == e.InitialOption
+		}
+		if !found {
+			return errors.Errorf("select initial option %q is not an option", e.InitialOption)
+		}
+	case *CardDatePickerElement:
+		if e.InitialDate != "" {
+			if _, err := time.Parse("2006-01-02", e.InitialDate); err != nil {
+				return errors.Errorf("date picker initial date %q is not yyyy-MM-dd", e.InitialDate)
+			}
+		}
+	case *CardColumnSetElement:
+		if columnDeep >= maxCardColumnDeep {
+			return errors.New("column set can not be nested")
+		}
+		if len(e.Columns) == 0 || len(e.Columns) > maxCardColumns {
+			return errors.Errorf("column set has %d columns, should be 1-%d", len(e.Columns), maxCardColumns)
+		}
+		for i, col := range e.Columns {
+			if col == nil {
+				return errors.Errorf("column %d is nil", i)
+			}
+			if col.Width == "weighted" && (col.Weight < 1 || col.Weight > 5) {
+				return errors.Errorf("column %d weight %d, should be 1-5", i, col.Weight)
+			}
+			for j, ce := range col.Elements {
+				if err := validateCardElement(ce, columnDeep+1); err != nil {
+					return errors.Wrapf(err, "column %d element %d", i, j)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+/** -------------------------------------------------消息卡片-------------------------------------------------------------------- **/
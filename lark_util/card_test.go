@@ -0,0 +1,126 @@
+package lark_util
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCardValidate(t *testing.T) {
+	button := CardLinkButton("查看", "https://example.com", ButtonTypePrimary)
+	column := func(elements ...CardElement) *CardColumn { return NewCardColumn(1, elements...) }
+	repeat := func(n int, e CardElement) []CardElement {
+		elements := make([]CardElement, n)
+		for i := range elements {
+			elements[i] = e
+		}
+		return elements
+	}
+	var nilNote *CardNoteElement
+	var nilButton *CardButtonElement
+
+	cases := []struct {
+		name    string
+		build   func(b *CardBuilder) *CardBuilder
+		wantErr string
+	}{
+		{"ok", func(b *CardBuilder) *CardBuilder {
+			return b.Markdown("**ok**").
+				Fields(CardShortField("a"), CardShortField("b")).
+				Element(&CardDivElement{Tag: "div", Text: CardPlainText("x"), Extra: CardImage("img_k", "alt")}).
+				Image("img_k", "alt").Hr().Note("note").
+				Actions(button, CardSelect("选择", nil, NewCardOption("A", "a")), CardDatePicker("日期", time.Now(), nil)).
+				Columns("none", column(CardMarkdown("l")), column(CardMarkdown("r")))
+		}, ""},
+		{"no elements", func(b *CardBuilder) *CardBuilder { return b }, "no elements"},
+		{"too many elements", func(b *CardBuilder) *CardBuilder {
+			return b.Element(repeat(maxCardElements+1, CardHr())...)
+		}, "at most"},
+		{"too large", func(b *CardBuilder) *CardBuilder {
+			return b.Markdown(strings.Repeat("x", maxCardSize))
+		}, "bytes"},
+		{"nil element", func(b *CardBuilder) *CardBuilder { return b.Element(nil) }, "nil element"},
+		{"typed nil element", func(b *CardBuilder) *CardBuilder { return b.Element(nilNote) }, "nil element"},
+		{"nil note child", func(b *CardBuilder) *CardBuilder { return b.Element(CardNote(nil)) }, "note element 0 is nil"},
+		{"note child type", func(b *CardBuilder) *CardBuilder { return b.Element(CardNote(CardHr())) }, "*lark_util.CardHrElement"},
+		{"note image without key", func(b *CardBuilder) *CardBuilder {
+			return b.Element(CardNote(CardImage("", "alt")))
+		}, "img_key"},
+		{"nil action", func(b *CardBuilder) *CardBuilder { return b.Actions(nil) }, "action 0 is nil"},
+		{"typed nil action", func(b *CardBuilder) *CardBuilder { return b.Actions(nilButton) }, "action 0 is nil"},
+		{"empty action", func(b *CardBuilder) *CardBuilder { return b.Actions() }, "0 actions"},
+		{"too many actions", func(b *CardBuilder) *CardBuilder {
+			return b.Actions(repeat(maxCardActions+1, button)...)
+		}, "21 actions"},
+		{"action type", func(b *CardBuilder) *CardBuilder { return b.Actions(CardHr()) }, "*lark_util.CardHrElement"},
+		{"button without text", func(b *CardBuilder) *CardBuilder {
+			return b.Actions(CardLinkButton("", "https://example.com", ""))
+		}, "button text is empty"},
+		{"button without url or value", func(b *CardBuilder) *CardBuilder {
+			return b.Actions(CardLinkButton("x", "", ""))
+		}, "neither url nor value"},
+		{"nil extra", func(b *CardBuilder) *CardBuilder {
+			return b.Element(&CardDivElement{Tag: "div", Text: CardPlainText("x"), Extra: nilButton})
+		}, "div extra is nil"},
+		{"extra type", func(b *CardBuilder) *CardBuilder {
+			return b.Element(&CardDivElement{Tag: "div", Text: CardPlainText("x"), Extra: CardHr()})
+		}, "*lark_util.CardHrElement"},
+		{"extra button without text", func(b *CardBuilder) *CardBuilder {
+			return b.Element(&CardDivElement{Tag: "div", Text: CardPlainText("x"), Extra: CardLinkButton("", "https://example.com", "")})
+		}, "button text is empty"},
+		{"empty div", func(b *CardBuilder) *CardBuilder { return b.Element(CardDiv(nil)) }, "neither text nor fields"},
+		{"select without options", func(b *CardBuilder) *CardBuilder {
+			return b.Actions(CardSelect("选择", nil))
+		}, "no options"},
+		{"select nil option", func(b *CardBuilder) *CardBuilder {
+			return b.Actions(CardSelect("选择", nil, nil))
+		}, "option 0 is nil"},
+		{"select initial option", func(b *CardBuilder) *CardBuilder {
+			s := CardSelect("选择", nil, NewCardOption("A", "a"))
+			s.InitialOption = "b"
+			return b.Actions(s)
+		}, "not an option"},
+		{"date picker format", func(b *CardBuilder) *CardBuilder {
+			return b.Actions(&CardDatePickerElement{Tag: "date_picker", InitialDate: "2026/10/19"})
+		}, "yyyy-MM-dd"},
+		{"no columns", func(b *CardBuilder) *CardBuilder { return b.Columns("none") }, "0 columns"},
+		{"too many columns", func(b *CardBuilder) *CardBuilder {
+			columns := make([]*CardColumn, maxCardColumns+1)
+			for i := range columns {
+				columns[i] = column(CardMarkdown("x"))
+			}
+			return b.Columns("none", columns...)
+		}, "6 columns"},
+		{"nil column", func(b *CardBuilder) *CardBuilder { return b.Columns("none", nil) }, "column 0 is nil"},
+		{"column weight", func(b *CardBuilder) *CardBuilder {
+			return b.Columns("none", &CardColumn{Tag: "column", Width: "weighted", Weight: 6})
+		}, "weight 6"},
+		{"nested column set", func(b *CardBuilder) *CardBuilder {
+			return b.Columns("none", column(CardColumnSet("none", column(CardMarkdown("x")))))
+		}, "can not be nested"},
+		{"nil column element", func(b *CardBuilder) *CardBuilder {
+			return b.Columns("none", column(nil))
+		}, "nil element"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := c.build(NewCardBuilder("标题", CardTemplateBlue)).Build()
+			if c.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+				t.Fatalf("error = %v, want containing %q", err, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestCardValidateHeader(t *testing.T) {
+	card := &Card{Header: &CardHeader{Title: CardPlainText("")}, Elements: []CardElement{CardHr()}}
+	if err := card.Validate(); err == nil || !strings.Contains(err.Error(), "header title") {
+		t.Fatalf("error = %v, want header title error", err)
+	}
+}
@@ -0,0 +1,130 @@
+package lark_util
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+/** -------------------------------------------------单元格值-------------------------------------------------------------- **/
+
+// 以下类型可以直接放在InsertValueToCellValueRange.Values中写入,读取时通过ParseCellValue还原
+
+type (
+	// FormulaValue 公式,如 =SUM(A1:A10)
+	FormulaValue struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	// LinkValue 超链接
+	LinkValue struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+		Link string `json:"link"`
+	}
+	// MentionValue @人或@文档
+	MentionValue struct {
+		Type                string `json:"type"`
+		Text                string `json:"text"`                          // 用户的邮箱或文档的token
+		TextType            string `json:"textType"`                      // email 或 fileToken
+		Notify              bool   `json:"notify,omitempty"`              // @人时是否发送通知
+		GrantReadPermission bool   `json:"grantReadPermission,omitempty"` // @人时是否授予被@用户阅读权限
+		ObjType             string `json:"objType,omitempty"`             // @文档时文档的类型,如 sheet、doc、docx、bitable
+		Link                string `json:"link,omitempty"`                // 读取时返回的链接
+	}
+	// DateValue 日期,写入为表格的日期序列号,需要配合日期格式(如FormatDateSlash)才会显示为日期
+	DateValue time.Time
+)
+
+// Formula 公式,text需要以=开头,不以=开头时会自动补上
+func Formula(text string) FormulaValue {
+	if !strings.HasPrefix(text, "=") {
+		text = "=" + text
+	}
+	return FormulaValue{Type: "formula", Text: text}
+}
+
+// Link 显示为text的超链接
+func Link(text, link string) LinkValue {
+	return LinkValue{Type: "url", Text: text, Link: link}
+}
+
+// MentionUser 通过邮箱@人,notify为是否通知被@的人,同时会授予其阅读权限
+func MentionUser(email string, notify bool) MentionValue {
+	return MentionValue{Type: "mention", Text: email, TextType: "email", Notify: notify, GrantReadPermission: true}
+}
+
+// MentionDocument @文档,objType为文档类型,如 sheet、doc、docx、bitable
+func MentionDocument(fileToken, objType string) MentionValue {
+	return MentionValue{Type: "mention", Text: fileToken, TextType: "fileToken", ObjType: objType}
+}
+
+// Date 日期值
+func Date(t time.Time) DateValue {
+	return DateValue(t)
+}
+
+func (d DateValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(excelSerial(time.Time(d)))
+}
+
+// Time 返回对应的time.Time
+func (d DateValue) Time() time.Time {
+	return time.Time(d)
+}
+
+// DateFromSerial 把表格的日期序列号(读取时设置ValueRenderUnformattedValue得到)转换为DateValue
+func DateFromSerial(serial float64) DateValue {
+	base := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	return DateValue(base.Add(time.Duration(serial * 24 * float64(time.Hour))).Round(time.Second))
+}
+
+// ParseCellValue 把GetCellValues读取到的值还原为本库的类型:链接为LinkValue,@人/@文档为MentionValue,
+// 以ValueRenderFormula读取时以=开头的字符串为FormulaValue;由多段组成的富文本返回[]interface{},只有一段时直接返回该段,
+// 其余值原样返回
+func ParseCellValue(v interface{}, valueRenderOption string) interface{} {
+	switch t := v.(type) {
+	case string:
+		if valueRenderOption == ValueRenderFormula && strings.HasPrefix(t, "=") {
+			return Formula(t)
+		}
+	case map[string]interface{}:
+		str := func(key string) string {
+			s, _ := t[key].(string)
+			return s
+		}
+		switch str("type") {
+		case "url":
+			return Link(str("text"), str("link"))
+		case "mention":
+			m := MentionValue{Type: "mention", Text: str("text"), TextType: str("textType"), ObjType: str("objType"), Link: str("link")}
+			m.Notify, _ = t["notify"].(bool)
+			m.GrantReadPermission, _ = t["grantReadPermission"].(bool)
+			return m
+		case "formula":
+			return Formula(str("text"))
+		}
+	case []interface{}:
+		segments := make([]interface{}, len(t))
+		for i, segment := range t {
+			segments[i] = ParseCellValue(segment, valueRenderOption)
+		}
+		if len(segments) == 1 {
+			return segments[0]
+		}
+		return segments
+	}
+	return v
+}
+
+// ParseCellValues 对二维数据中的每个值调用ParseCellValue,原地替换
+func ParseCellValues(values [][]interface{}, valueRenderOption string) [][]interface{} {
+	for _, row := range values {
+		for j, v := range row {
+			row[j] = ParseCellValue(v, valueRenderOption)
+		}
+	}
+	return values
+}
+
+/** -------------------------------------------------单元格值-------------------------------------------------------------- **/
@@ -0,0 +1,397 @@
+package lark_util
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+/** -------------------------------------------------群组-------------------------------------------------------------------- **/
+
+// 群成员id类型,除了UserIdType开头的常量外还可以是应用
+const MemberIdTypeAppId = "app_id"
+
+type (
+	Chat struct {
+		ChatId                 string `json:"chat_id,omitempty"`
+		Name                   string `json:"name,omitempty"`
+		Description            string `json:"description,omitempty"`
+		Avatar                 string `json:"avatar,omitempty"`
+		OwnerId                string `json:"owner_id,omitempty"`
+		OwnerIdType            string `json:"owner_id_type,omitempty"`
+		ChatMode               string `json:"chat_mode,omitempty"` // group、topic、p2p
+		ChatType               string `json:"chat_type,omitempty"` // private、public
+		ChatStatus             string `json:"chat_status,omitempty"`
+		External               bool   `json:"external,omitempty"`
+		TenantKey              string `json:"tenant_key,omitempty"`
+		UserCount              string `json:"user_count,omitempty"`
+		BotCount               string `json:"bot_count,omitempty"`
+		AddMemberPermission    string `json:"add_member_permission,omitempty"`
+		ShareCardPermission    string `json:"share_card_permission,omitempty"`
+		MembershipApproval     string `json:"membership_approval,omitempty"`
+		ModerationPermission   string `json:"moderation_permission,omitempty"`
+		EditPermission         string `json:"edit_permission,omitempty"`
+		AtAllPermission        string `json:"at_all_permission,omitempty"`
+		JoinMessageVisibility  string `json:"join_message_visibility,omitempty"`
+		LeaveMessageVisibility string `json:"leave_message_visibility,omitempty"`
+	}
+	CreateChatReq struct {
+		Name          string
+		Description   string
+		Avatar        string   // 头像的image_key
+		OwnerId       string   // 群主,为空时群主是应用
+		UserIds       []string // 初始成员,最多50个
+		BotIds        []string // 初始机器人的app_id,最多5个
+		ChatType      string   // private或public,默认private
+		UserIdType    string   // OwnerId、UserIds的类型,见UserIdType开头的常量,默认open_id
+		SetBotManager bool     // 是否把应用设为群管理员
+		Uuid          string   // 不为空时,10小时内相同uuid的请求只创建一个群
+	}
+	// UpdateChatReq 修改群信息,为空的字段不修改
+	UpdateChatReq struct {
+		Name        string
+		Description string
+		Avatar      string
+		OwnerId     string // 转让群主
+		UserIdType  string
+	}
+	// ChatMembersResult 添加或移除群成员时无法处理的id
+	ChatMembersResult struct {
+		InvalidIdList         []string `json:"invalid_id_list,omitempty"`          // 无效的id
+		NotExistedIdList      []string `json:"not_existed_id_list,omitempty"`      // 不存在的id
+		PendingApprovalIdList []string `json:"pending_approval_id_list,omitempty"` // 等待群主或管理员审批的id
+	}
+	// ChatAnnouncement 群公告,内容为旧版文档的json
+	ChatAnnouncement struct {
+		Content        string `json:"content"`
+		Revision       string `json:"revision"`
+		CreateTime     string `json:"create_time,omitempty"`
+		UpdateTime     string `json:"update_time,omitempty"`
+		OwnerId        string `json:"owner_id,omitempty"`
+		ModifierId     string `json:"modifier_id,omitempty"`
+		OwnerIdType    string `json:"owner_id_type,omitempty"`
+		ModifierIdType string `json:"modifier_id_type,omitempty"`
+	}
+)
+
+// CreateChat 创建群,返回群信息
+func (l *LarkU) CreateChat(req *CreateChatReq) (chat *Chat, err error) {
+	values := url.Values{}
+	if req.UserIdType != "" {
+		values.Set("user_id_type", req.UserIdType)
+	}
+	if req.SetBotManager {
+		values.Set("set_bot_manager", "true")
+	}
+	if req.Uuid != "" {
+		values.Set("uuid", req.Uuid)
+	}
+	param := map[string]interface{}{
+		"name":        req.Name,
+		"description": req.Description,
+		"chat_mode":   "group",
+	}
+	if req.Avatar != "" {
+		param["avatar"] = req.Avatar
+	}
+	if req.OwnerId != "" {
+		param["owner_id"] = req.OwnerId
+	}
+	if len(req.UserIds) > 0 {
+		param["user_id_list"] = req.UserIds
+	}
+	if len(req.BotIds) > 0 {
+		param["bot_id_list"] = req.BotIds
+	}
+	if req.ChatType != "" {
+		param["chat_type"] = req.ChatType
+	}
+	httpCode, respBody, err := l.LarkPost("/open-apis/im/v1/chats?"+values.Encode(), param)
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type CreateChatResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data *Chat  `json:"data,omitempty"`
+	}
+	m := new(CreateChatResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	chat = m.Data
+	return
+}
+
+// UpdateChat 修改群名称、描述、头像或转让群主
+func (l *LarkU) UpdateChat(chatId string, req *UpdateChatReq) (err error) {
+	param := map[string]interface{}{}
+	if req.Name != "" {
+		param["name"] = req.Name
+	}
+	if req.Description != "" {
+		param["description"] = req.Description
+	}
+	if req.Avatar != "" {
+		param["avatar"] = req.Avatar
+	}
+	if req.OwnerId != "" {
+		param["owner_id"] = req.OwnerId
+	}
+	httpCode, respBody, err := l.LarkPut("/open-apis/im/v1/chats/"+url.PathEscape(chatId)+"?"+contactIdQuery(req.UserIdType, "").Encode(), param)
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type UpdateChatResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+	}
+	m := new(UpdateChatResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+	}
+	return
+}
+
+// GetChat 获取群信息,返回的Chat没有ChatId
+func (l *LarkU) GetChat(chatId, userIdType string) (chat *Chat, err error) {
+	httpCode, respBody, err := l.LarkGet("/open-apis/im/v1/chats/"+url.PathEscape(chatId), contactIdQuery(userIdType, ""))
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type GetChatResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data *Chat  `json:"data,omitempty"`
+	}
+	m := new(GetChatResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	chat = m.Data
+	return
+}
+
+// IterChats 逐页遍历应用所在的群
+func (l *LarkU) IterChats(ctx context.Context, userIdType string) *Pager[*Chat] {
+	values := contactIdQuery(userIdType, "")
+	values.Set("page_size", "100")
+	return larkGetPager[*Chat](ctx, l, "/open-apis/im/v1/chats", values, "items")
+}
+
+// ListChats 列出应用所在的所有群
+func (l *LarkU) ListChats(userIdType string) (chats []*Chat, err error) {
+	return l.IterChats(context.Background(), userIdType).All()
+}
+
+// AddChatMembers 拉用户或机器人进群,memberIdType见UserIdType开头的常量或MemberIdTypeAppId
+func (l *LarkU) AddChatMembers(chatId, memberIdType string, ids []string) (result *ChatMembersResult, err error) {
+	return l.chatMembers(http.MethodPost, chatId, memberIdType, ids)
+}
+
+// RemoveChatMembers 把用户或机器人移出群
+func (l *LarkU) RemoveChatMembers(chatId, memberIdType string, ids []string) (result *ChatMembersResult, err error) {
+	return l.chatMembers(http.MethodDelete, chatId, memberIdType, ids)
+}
+
+// AddChatMembersByEmail 按邮箱拉用户进群,找不到的邮箱不处理并返回ErrUserNotFound
+func (l *LarkU) AddChatMembersByEmail(chatId string, emails ...string) (result *ChatMembersResult, err error) {
+	return l.chatMembersByEmail(http.MethodPost, chatId, emails)
+}
+
+// RemoveChatMembersByEmail 按邮箱把用户移出群,找不到的邮箱不处理并返回ErrUserNotFound
+func (l *LarkU) RemoveChatMembersByEmail(chatId string, emails ...string) (result *ChatMembersResult, err error) {
+	return l.chatMembersByEmail(http.MethodDelete, chatId, emails)
+}
+
+func (l *LarkU) chatMembersByEmail(method, chatId string, emails []string) (result *ChatMembersResult, err error) {
+	results, err := l.ResolveUserIds(&ResolveUserIdsReq{Emails: emails, UserIdType: UserIdTypeOpenId})
+	if err != nil {
+		return
+	}
+	var ids, notFound []string
+	for _, email := range emails {
+		if r := results[email]; r.Found {
+			ids = append(ids, r.UserId)
+		} else {
+			notFound = append(notFound, email)
+		}
+	}
+	if len(ids) > 0 {
+		if result, err = l.chatMembers(method, chatId, UserIdTypeOpenId, ids); err != nil {
+			return
+		}
+	}
+	if len(notFound) > 0 {
+		err = errors.Wrapf(ErrUserNotFound, "emails %s", strings.Join(notFound, ", "))
+	}
+	return
+}
+
+func (l *LarkU) chatMembers(method, chatId, memberIdType string, ids []string) (result *ChatMembersResult, err error) {
+	path := "/open-apis/im/v1/chats/" + url.PathEscape(chatId) + "/members?" + url.Values{"member_id_type": {memberIdType}}.Encode()
+	param := map[string]interface{}{"id_list": ids}
+	var httpCode int
+	var respBody []byte
+	if method == http.MethodDelete {
+		httpCode, respBody, err = l.LarkDelete(path, param)
+	} else {
+		httpCode, respBody, err = l.LarkPost(path, param)
+	}
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type ChatMembersResp struct {
+		Code int32              `json:"code,omitempty"`
+		Msg  string             `json:"msg,omitempty"`
+		Data *ChatMembersResult `json:"data,omitempty"`
+	}
+	m := new(ChatMembersResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	result = m.Data
+	if result == nil {
+		result = &ChatMembersResult{}
+	}
+	return
+}
+
+// GetChatAnnouncement 获取群公告
+func (l *LarkU) GetChatAnnouncement(chatId string) (announcement *ChatAnnouncement, err error) {
+	httpCode, respBody, err := l.LarkGet("/open-apis/im/v1/chats/"+url.PathEscape(chatId)+"/announcement", nil)
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type GetChatAnnouncementResp struct {
+		Code int32             `json:"code,omitempty"`
+		Msg  string            `json:"msg,omitempty"`
+		Data *ChatAnnouncement `json:"data,omitempty"`
+	}
+	m := new(GetChatAnnouncementResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	if m.Data == nil {
+		err = errors.Errorf("remote service error: empty chat announcement | %s", string(respBody))
+		return
+	}
+	announcement = m.Data
+	return
+}
+
+// UpdateChatAnnouncement 修改群公告,requests为旧版文档的修改请求,revision为GetChatAnnouncement返回的版本号
+func (l *LarkU) UpdateChatAnnouncement(chatId, revision string, requests []string) (err error) {
+	httpCode, respBody, err := l.LarkPatch("/open-apis/im/v1/chats/"+url.PathEscape(chatId)+"/announcement", map[string]interface{}{
+		"revision": revision,
+		"requests": requests,
+	})
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type UpdateChatAnnouncementResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+	}
+	m := new(UpdateChatAnnouncementResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+	}
+	return
+}
+
+// SetChatAnnouncement 在群公告开头插入一段文本,已有的公告内容保留在其后
+func (l *LarkU) SetChatAnnouncement(chatId, text string) (err error) {
+	announcement, err := l.GetChatAnnouncement(chatId)
+	if err != nil {
+		return
+	}
+	payload, _ := json.Marshal(map[string]interface{}{
+		"blocks": []interface{}{map[string]interface{}{
+			"type": "paragraph",
+			"paragraph": map[string]interface{}{
+				"elements": []interface{}{map[string]interface{}{
+					"type":    "textRun",
+					"textRun": map[string]interface{}{"text": text, "style": map[string]interface{}{}},
+				}},
+			},
+		}},
+	})
+	request, _ := json.Marshal(map[string]interface{}{
+		"requestType": "InsertBlocksRequestType",
+		"insertBlocksRequest": map[string]interface{}{
+			"payload":  string(payload),
+			"location": map[string]interface{}{"zoneId": "0", "index": 0, "startOfZone": true},
+		},
+	})
+	return l.UpdateChatAnnouncement(chatId, announcement.Revision, []string{string(request)})
+}
+
+// DisbandChat 解散群,应用需要是群主
+func (l *LarkU) DisbandChat(chatId string) (err error) {
+	httpCode, respBody, err := l.LarkDelete("/open-apis/im/v1/chats/"+url.PathEscape(chatId), map[string]interface{}{})
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type DisbandChatResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+	}
+	m := new(DisbandChatResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+	}
+	return
+}
+
+/** -------------------------------------------------群组-------------------------------------------------------------------- **/
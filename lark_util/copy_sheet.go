@@ -0,0 +1,168 @@
+package lark_util
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+/** -------------------------------------------------跨表格复制-------------------------------------------------------------- **/
+
+type CopySheetToReq struct {
+	SrcExcelToken string
+	SrcSheetId    string
+	DstExcelToken string
+	Title         string      // 目标sheet标题,默认与源sheet相同,不能与目标表格中已有的sheet重复
+	ChunkRows     int         // 每次读取/写入的行数,默认500
+	ColumnWidths  []int       // 目标sheet的列宽,开放接口无法读取源sheet的列宽,需要调用方提供
+	Styles        []StyleSpec // 目标sheet的样式,开放接口无法读取源sheet的样式,需要调用方提供
+}
+
+// CopySheetTo 把sheet复制到另一个表格,返回新sheet的id.HandleSheet的copySheet只能在同一个表格内复制,
+// 这里通过读取源sheet并重放到目标表格实现:复制数据(公式、链接、@保持原样)、合并单元格和冻结行列;
+// 列宽和样式开放接口无法读取,由ColumnWidths、Styles指定
+func (l *LarkU) CopySheetTo(ctx context.Context, req *CopySheetToReq) (dstSheetId string, err error) {
+	chunk := req.ChunkRows
+	if chunk <= 0 {
+		chunk = 500
+	}
+	info, err := l.GetExcelInfo(req.SrcExcelToken, "", "")
+	if err != nil {
+		return
+	}
+	var src *Sheets
+	for _, s := range info.Sheets {
+		if s.SheetId == req.SrcSheetId {
+			src = s
+		}
+	}
+	if src == nil {
+		err = errors.Wrapf(ErrSheetNotFound, "sheet %s in %s", req.SrcSheetId, req.SrcExcelToken)
+		return
+	}
+	if src.BlockInfo.BlockToken != "" {
+		err = errors.Errorf("sheet %s is a %s block, not a grid", src.SheetId, src.BlockInfo.BlockType)
+		return
+	}
+	title := req.Title
+	if title == "" {
+		title = src.Title
+	}
+
+	dstInfo, err := l.GetExcelInfo(req.DstExcelToken, "", "")
+	if err != nil {
+		return
+	}
+	if err = l.ExecSheetBatch(NewSheetBatch(req.DstExcelToken).AddSheet(title, len(dstInfo.Sheets))); err != nil {
+		err = errors.Wrap(err, "add target sheet")
+		return
+	}
+	dst, err := l.SheetByTitle(req.DstExcelToken, title)
+	if err != nil {
+		return
+	}
+	dstSheetId = dst.SheetId
+	if need := src.RowCount - dst.RowCount; need > 0 {
+		if err = l.growDimension(req.DstExcelToken, dstSheetId, MajorDimensionRows, need); err != nil {
+			return
+		}
+	}
+	if need := src.ColumnCount - dst.ColumnCount; need > 0 {
+		if err = l.growDimension(req.DstExcelToken, dstSheetId, MajorDimensionCols, need); err != nil {
+			return
+		}
+	}
+
+	for start := 0; start < src.RowCount && src.ColumnCount > 0; start += chunk {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		end := start + chunk
+		if end > src.RowCount {
+			end = src.RowCount
+		}
+		valueRange, e := l.GetCellValues(req.SrcExcelToken, SheetRange(src.SheetId, 0, start, src.ColumnCount-1, end-1), ValueRenderFormula, "")
+		if e != nil {
+			err = errors.Wrapf(e, "read rows %d-%d", start+1, end)
+			return
+		}
+		values := ParseCellValues(valueRange.Values, ValueRenderFormula)
+		for _, row := range values {
+			for j, v := range row {
+				if v == nil {
+					row[j] = ""
+				}
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+		err = l.BatchWriteValueToCell(&BatchWriteValueToCellReq{
+			ExcelToken:  req.DstExcelToken,
+			ValueRanges: splitValueRanges(dstSheetId, start, values, src.ColumnCount),
+		})
+		if err != nil {
+			err = errors.Wrapf(err, "write rows %d-%d", start+1, end)
+			return
+		}
+	}
+
+	for _, merge := range src.Merges {
+		cellRange := CellRef(merge.StartColumnIndex, merge.StartRowIndex) + ":" +
+			CellRef(merge.StartColumnIndex+merge.ColumnCount-1, merge.StartRowIndex+merge.RowCount-1)
+		if err = l.MergeCells(req.DstExcelToken, dstSheetId, cellRange, MergeCellTypeAll); err != nil {
+			err = errors.Wrapf(err, "merge %s", cellRange)
+			return
+		}
+	}
+	if len(req.Styles) > 0 {
+		data := make([]Data, 0, len(req.Styles))
+		for _, style := range req.Styles {
+			ranges := make([]string, len(style.Ranges))
+			for i, r := range style.Ranges {
+				ranges[i] = dstSheetId + "!" + r
+			}
+			data = append(data, Data{Ranges: ranges, Style: style.Style})
+		}
+		if err = l.BatchUpdateCellStyle(&BatchUpdateCellStyleReq{ExcelToken: req.DstExcelToken, Data: mergeStyleData(data)}); err != nil {
+			err = errors.Wrap(err, "set styles")
+			return
+		}
+	}
+	if src.FrozenRowCount > 0 || src.FrozenColCount > 0 {
+		if err = l.ExecSheetBatch(NewSheetBatch(req.DstExcelToken).Freeze(dstSheetId, src.FrozenRowCount, src.FrozenColCount)); err != nil {
+			err = errors.Wrap(err, "freeze")
+			return
+		}
+	}
+	if err = l.setDimensionSizes(req.DstExcelToken, dstSheetId, MajorDimensionCols, req.ColumnWidths); err != nil {
+		err = errors.Wrap(err, "set column widths")
+	}
+	return
+}
+
+// ReorderSheets 按sheetIds的顺序一次性重排所有sheet,sheetIds需要包含表格中的每个sheet且不重复
+func (l *LarkU) ReorderSheets(excelToken string, sheetIds []string) (err error) {
+	info, err := l.GetExcelInfo(excelToken, "", "")
+	if err != nil {
+		return
+	}
+	if len(sheetIds) != len(info.Sheets) {
+		return errors.Errorf("got %d sheet ids, spreadsheet has %d sheets", len(sheetIds), len(info.Sheets))
+	}
+	seen := make(map[string]bool, len(sheetIds))
+	batch := NewSheetBatch(excelToken)
+	for i, sheetId := range sheetIds {
+		if seen[sheetId] {
+			return errors.Errorf("duplicate sheet id %s", sheetId)
+		}
+		seen[sheetId] = true
+		batch.Move(sheetId, i)
+	}
+	if err = batch.Validate(info); err != nil {
+		return
+	}
+	return l.HandleSheet(batch.Request())
+}
+
+/** -------------------------------------------------跨表格复制-------------------------------------------------------------- **/
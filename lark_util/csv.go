@@ -0,0 +1,258 @@
+package lark_util
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+/** -------------------------------------------------csv写入----------------------------------------------------------------- **/
+
+const (
+	maxWriteRows = 5000 // 单次写入/增加行列的上限
+	maxWriteCols = 100  // 单次写入的列数上限
+)
+
+// DefaultCSVDateLayouts CSVToSheet 默认识别的日期格式
+var DefaultCSVDateLayouts = []string{
+	"2006-01-02",
+	"2006/01/02",
+	"2006-01-02 15:04:05",
+	"2006/01/02 15:04:05",
+	time.RFC3339,
+}
+
+type CSVToSheetReq struct {
+	ExcelToken  string
+	SheetId     string
+	StartCell   string      // 写入的起始单元格,默认A1
+	Reader      *csv.Reader // 数据来源,按批读取,不会一次性读入内存
+	BatchRows   int         // 每批写入的行数,默认1000,不超过5000
+	DateLayouts []string    // 识别为日期的格式,默认DefaultCSVDateLayouts
+}
+
+// CSVToSheet 把csv流式写入已存在的sheet,返回写入的行数.
+// 单元格会推断类型:以"="开头的为公式,数字、true/false、日期分别写为数字、布尔值和带日期格式的数字,其余为文本;
+// sheet的行列数不够时会通过AddDimension自动增加
+func (l *LarkU) CSVToSheet(ctx context.Context, req *CSVToSheetReq) (rows int, err error) {
+	if req.StartCell == "" {
+		req.StartCell = "A1"
+	}
+	if req.BatchRows <= 0 || req.BatchRows > maxWriteRows {
+		req.BatchRows = 1000
+	}
+	if len(req.DateLayouts) == 0 {
+		req.DateLayouts = DefaultCSVDateLayouts
+	}
+	startCol, startRow, err := ParseCellRef(req.StartCell)
+	if err != nil {
+		return
+	}
+	info, err := l.GetExcelInfo(req.ExcelToken, "", "")
+	if err != nil {
+		return
+	}
+	var sheet *Sheets
+	for _, s := range info.Sheets {
+		if s.SheetId == req.SheetId {
+			sheet = s
+		}
+	}
+	if sheet == nil {
+		err = errors.Errorf("sheet %s not found in %s", req.SheetId, req.ExcelToken)
+		return
+	}
+	rowCount, colCount := sheet.RowCount, sheet.ColumnCount
+
+	batch := make([][]string, 0, req.BatchRows)
+	for eof := false; !eof; {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		batch = batch[:0]
+		for len(batch) < req.BatchRows {
+			record, e := req.Reader.Read()
+			if e == io.EOF {
+				eof = true
+				break
+			}
+			if e != nil {
+				err = errors.Wrapf(e, "read csv row %d", rows+len(batch)+1)
+				return
+			}
+			batch = append(batch, record)
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		width := 0
+		for _, record := range batch {
+			if len(record) > width {
+				width = len(record)
+			}
+		}
+		firstRow := startRow + rows
+		if need := firstRow + len(batch) - rowCount; need > 0 {
+			if err = l.growDimension(req.ExcelToken, req.SheetId, MajorDimensionRows, need); err != nil {
+				return
+			}
+			rowCount += need
+		}
+		if need := startCol + width - colCount; need > 0 {
+			if err = l.growDimension(req.ExcelToken, req.SheetId, MajorDimensionCols, need); err != nil {
+				return
+			}
+			colCount += need
+		}
+
+		values := make([][]interface{}, len(batch))
+		formatters := make([][]string, len(batch))
+		for i, record := range batch {
+			values[i] = make([]interface{}, width)
+			formatters[i] = make([]string, width)
+			for j := range values[i] {
+				if j >= len(record) {
+					values[i][j] = ""
+					continue
+				}
+				values[i][j], formatters[i][j] = inferCSVValue(record[j], req.DateLayouts)
+			}
+		}
+		// 同一列中连续且格式相同的日期单元格合并为一个范围
+		var dateStyles []Data
+		for j := 0; j < width; j++ {
+			for i := 0; i < len(batch); {
+				k := i
+				for k < len(batch) && formatters[k][j] == formatters[i][j] {
+					k++
+				}
+				if formatters[i][j] != "" {
+					dateStyles = append(dateStyles, Data{
+						Ranges: []string{SheetRange(req.SheetId, startCol+j, firstRow+i, startCol+j, firstRow+k-1)},
+						Style:  Style{Formatter: formatters[i][j]},
+					})
+				}
+				i = k
+			}
+		}
+		for c := 0; c < width; c += maxWriteCols {
+			end := c + maxWriteCols
+			if end > width {
+				end = width
+			}
+			part := make([][]interface{}, len(values))
+			for i := range values {
+				part[i] = values[i][c:end]
+			}
+			err = l.WriteValueToCell(&WriteValueToCellReq{
+				ExcelToken: req.ExcelToken,
+				ValueRange: InsertValueToCellValueRange{
+					Range:  SheetRange(req.SheetId, startCol+c, firstRow, startCol+end-1, firstRow+len(batch)-1),
+					Values: part,
+				},
+			})
+			if err != nil {
+				err = errors.Wrapf(err, "write csv rows %d-%d", rows+1, rows+len(batch))
+				return
+			}
+		}
+		if len(dateStyles) > 0 {
+			err = l.BatchUpdateCellStyle(&BatchUpdateCellStyleReq{ExcelToken: req.ExcelToken, Data: mergeStyleData(dateStyles)})
+			if err != nil {
+				err = errors.Wrapf(err, "set date format of csv rows %d-%d", rows+1, rows+len(batch))
+				return
+			}
+		}
+		rows += len(batch)
+	}
+	return
+}
+
+// growDimension 在sheet末尾增加length行/列,超过单次上限时分多次增加
+func (l *LarkU) growDimension(excelToken, sheetId, majorDimension string, length int) (err error) {
+	for length > 0 {
+		n := length
+		if n > maxWriteRows-1 {
+			n = maxWriteRows - 1
+		}
+		err = l.AddDimension(&AddDimensionReq{
+			ExcelToken: excelToken,
+			Dimension: &DimensionAdd{
+				SheetID:        sheetId,
+				MajorDimension: majorDimension,
+				Length:         n,
+			},
+		})
+		if err != nil {
+			return
+		}
+		length -= n
+	}
+	return
+}
+
+// mergeStyleData 把样式相同的范围合并到同一个Data中,减少请求体积
+func mergeStyleData(data []Data) (merged []Data) {
+	index := make(map[Style]int)
+	for _, d := range data {
+		if i, ok := index[d.Style]; ok {
+			merged[i].Ranges = append(merged[i].Ranges, d.Ranges...)
+			continue
+		}
+		index[d.Style] = len(merged)
+		merged = append(merged, Data{Ranges: append([]string(nil), d.Ranges...), Style: d.Style})
+	}
+	return
+}
+
+// inferCSVValue 推断csv单元格的类型,日期返回序列号以及要设置的数字格式
+func inferCSVValue(s string, dateLayouts []string) (v interface{}, formatter string) {
+	t := strings.TrimSpace(s)
+	switch {
+	case t == "":
+		return s, ""
+	case strings.HasPrefix(t, "="):
+		return Formula(t), ""
+	case strings.EqualFold(t, "true"):
+		return true, ""
+	case strings.EqualFold(t, "false"):
+		return false, ""
+	}
+	// 以0开头的多位数字(如编号、邮编)以及超过15位的数字按文本处理,避免丢失前导0和精度
+	digits := strings.TrimPrefix(t, "-")
+	if digits != "" && !(len(digits) > 1 && digits[0] == '0' && digits[1] != '.') && len(digits) <= 15 {
+		if i, err := strconv.ParseInt(t, 10, 64); err == nil {
+			return i, ""
+		}
+		if f, err := strconv.ParseFloat(t, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+			return f, ""
+		}
+	}
+	for _, layout := range dateLayouts {
+		if d, err := time.ParseInLocation(layout, t, time.UTC); err == nil {
+			formatter = FormatDateSlash
+			if d.Hour() != 0 || d.Minute() != 0 || d.Second() != 0 {
+				formatter = FormatDateTimeSlash
+			}
+			return excelSerial(d), formatter
+		}
+	}
+	return s, ""
+}
+
+// excelSerial 返回时间对应的表格日期序列号,即距1899-12-30的天数
+func excelSerial(t time.Time) float64 {
+	y, m, d := t.Date()
+	base := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	wall := time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	return wall.Sub(base).Hours() / 24
+}
+
+/** -------------------------------------------------csv写入----------------------------------------------------------------- **/
@@ -0,0 +1,206 @@
+package lark_util
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+/** -------------------------------------------------部门-------------------------------------------------------------------- **/
+
+// RootDepartmentId 根部门的id,与department_id_type无关
+const RootDepartmentId = "0"
+
+type (
+	Department struct {
+		Name               string            `json:"name,omitempty"`
+		DepartmentId       string            `json:"department_id,omitempty"`
+		OpenDepartmentId   string            `json:"open_department_id,omitempty"`
+		ParentDepartmentId string            `json:"parent_department_id,omitempty"` // 类型由department_id_type决定
+		LeaderUserId       string            `json:"leader_user_id,omitempty"`       // 类型由user_id_type决定
+		ChatId             string            `json:"chat_id,omitempty"`              // 部门群id
+		Order              string            `json:"order,omitempty"`
+		MemberCount        int               `json:"member_count,omitempty"`
+		Status             *DepartmentStatus `json:"status,omitempty"`
+	}
+	DepartmentStatus struct {
+		IsDeleted bool `json:"is_deleted,omitempty"`
+	}
+	ListDepartmentReq struct {
+		DepartmentId     string
+		UserIdType       string // 见UserIdType开头的常量,默认open_id
+		DepartmentIdType string // 见DepartmentIdType开头的常量,默认open_department_id
+		FetchChild       bool   // 列出子部门时是否递归获取所有下级部门
+		PageSize         int    // 默认10,最大50
+		PageToken        string
+	}
+	// DepartmentNode 部门树的节点,Children按接口返回的顺序排列
+	DepartmentNode struct {
+		*Department
+		Children []*DepartmentNode
+	}
+)
+
+// Id 返回departmentIdType对应的部门id
+func (d *Department) Id(departmentIdType string) string {
+	if departmentIdType == DepartmentIdTypeDepartmentId {
+		return d.DepartmentId
+	}
+	return d.OpenDepartmentId
+}
+
+// Walk 先序遍历部门树,depth从0开始;fn返回false时不再遍历该节点的下级部门
+func (n *DepartmentNode) Walk(fn func(node *DepartmentNode, depth int) bool) {
+	var walk func(node *DepartmentNode, depth int)
+	walk = func(node *DepartmentNode, depth int) {
+		if !fn(node, depth) {
+			return
+		}
+		for _, child := range node.Children {
+			walk(child, depth+1)
+		}
+	}
+	walk(n, 0)
+}
+
+func (req *ListDepartmentReq) query() url.Values {
+	values := contactIdQuery(req.UserIdType, req.DepartmentIdType)
+	if req.PageSize > 0 {
+		values.Set("page_size", strconv.Itoa(req.PageSize))
+	}
+	return values
+}
+
+func (req *ListDepartmentReq) childrenPath() string {
+	return "/open-apis/contact/v3/departments/" + url.PathEscape(req.DepartmentId) + "/children"
+}
+
+func (req *ListDepartmentReq) childrenQuery() url.Values {
+	values := req.query()
+	values.Set("fetch_child", strconv.FormatBool(req.FetchChild))
+	return values
+}
+
+// departmentQuery 用于department_id放在查询参数中的接口
+func (req *ListDepartmentReq) departmentQuery() url.Values {
+	values := req.query()
+	values.Set("department_id", req.DepartmentId)
+	return values
+}
+
+func (req *ListDepartmentReq) withPageSize(pageSize int) *ListDepartmentReq {
+	r := *req
+	r.PageSize = pageSize
+	return &r
+}
+
+// GetDepartment 获取部门信息,userIdType见UserIdType开头的常量,departmentIdType见DepartmentIdType开头的常量
+func (l *LarkU) GetDepartment(departmentId, userIdType, departmentIdType string) (department *Department, err error) {
+	httpCode, respBody, err := l.LarkGet("/open-apis/contact/v3/departments/"+url.PathEscape(departmentId), contactIdQuery(userIdType, departmentIdType))
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type GetDepartmentResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data struct {
+			Department *Department `json:"department,omitempty"`
+		}
+	}
+	m := new(GetDepartmentResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	department = m.Data.Department
+	return
+}
+
+// ListChildDepartments 列出一页子部门,req.FetchChild为true时包含所有下级部门;hasMore为true时用nextPageToken继续获取
+func (l *LarkU) ListChildDepartments(req *ListDepartmentReq) (departments []*Department, nextPageToken string, hasMore bool, err error) {
+	return larkGetPage[*Department](l, req.childrenPath(), req.childrenQuery(), "items", req.PageToken)
+}
+
+// IterChildDepartments 逐页遍历子部门,req.PageToken会被忽略
+func (l *LarkU) IterChildDepartments(ctx context.Context, req *ListDepartmentReq) *Pager[*Department] {
+	return larkGetPager[*Department](ctx, l, req.childrenPath(), req.childrenQuery(), "items")
+}
+
+// ListAllChildDepartments 列出所有子部门
+func (l *LarkU) ListAllChildDepartments(req *ListDepartmentReq) (departments []*Department, err error) {
+	return l.IterChildDepartments(context.Background(), req.withPageSize(50)).All()
+}
+
+// ListParentDepartments 列出一页上级部门,从直接上级到根部门的下一级
+func (l *LarkU) ListParentDepartments(req *ListDepartmentReq) (departments []*Department, nextPageToken string, hasMore bool, err error) {
+	return larkGetPage[*Department](l, "/open-apis/contact/v3/departments/parent", req.departmentQuery(), "items", req.PageToken)
+}
+
+// IterParentDepartments 逐页遍历上级部门,req.PageToken会被忽略
+func (l *LarkU) IterParentDepartments(ctx context.Context, req *ListDepartmentReq) *Pager[*Department] {
+	return larkGetPager[*Department](ctx, l, "/open-apis/contact/v3/departments/parent", req.departmentQuery(), "items")
+}
+
+// ListAllParentDepartments 列出部门的完整上级链,从直接上级到根部门的下一级
+func (l *LarkU) ListAllParentDepartments(req *ListDepartmentReq) (departments []*Department, err error) {
+	return l.IterParentDepartments(context.Background(), req.withPageSize(50)).All()
+}
+
+// ListDepartmentUsers 列出一页直属于部门的用户,不包含下级部门的用户
+func (l *LarkU) ListDepartmentUsers(req *ListDepartmentReq) (users []*User, nextPageToken string, hasMore bool, err error) {
+	return larkGetPage[*User](l, "/open-apis/contact/v3/users/find_by_department", req.departmentQuery(), "items", req.PageToken)
+}
+
+// IterDepartmentUsers 逐页遍历直属于部门的用户,req.PageToken会被忽略
+func (l *LarkU) IterDepartmentUsers(ctx context.Context, req *ListDepartmentReq) *Pager[*User] {
+	return larkGetPager[*User](ctx, l, "/open-apis/contact/v3/users/find_by_department", req.departmentQuery(), "items")
+}
+
+// ListAllDepartmentUsers 列出所有直属于部门的用户
+func (l *LarkU) ListAllDepartmentUsers(req *ListDepartmentReq) (users []*User, err error) {
+	return l.IterDepartmentUsers(context.Background(), req.withPageSize(50)).All()
+}
+
+// DepartmentTree 获取部门及其所有下级部门,组装成树.req.FetchChild和分页参数会被忽略;
+// departmentId为RootDepartmentId时根节点只有id,没有名称等信息
+func (l *LarkU) DepartmentTree(req *ListDepartmentReq) (root *DepartmentNode, err error) {
+	var department *Department
+	if req.DepartmentId == RootDepartmentId {
+		department = &Department{DepartmentId: RootDepartmentId, OpenDepartmentId: RootDepartmentId}
+	} else if department, err = l.GetDepartment(req.DepartmentId, req.UserIdType, req.DepartmentIdType); err != nil {
+		return
+	}
+	fetch := req.withPageSize(50)
+	fetch.FetchChild = true
+	departments, err := l.ListAllChildDepartments(fetch)
+	if err != nil {
+		return
+	}
+	root = &DepartmentNode{Department: department}
+	nodes := make(map[string]*DepartmentNode, len(departments)+1)
+	nodes[req.DepartmentId] = root
+	for _, d := range departments {
+		nodes[d.Id(req.DepartmentIdType)] = &DepartmentNode{Department: d}
+	}
+	for _, d := range departments {
+		parent, ok := nodes[d.ParentDepartmentId]
+		if !ok {
+			err = errors.Errorf("parent %s of department %s not found in subtree", d.ParentDepartmentId, d.Id(req.DepartmentIdType))
+			return
+		}
+		parent.Children = append(parent.Children, nodes[d.Id(req.DepartmentIdType)])
+	}
+	return
+}
+
+/** -------------------------------------------------部门-------------------------------------------------------------------- **/
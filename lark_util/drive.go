@@ -0,0 +1,274 @@
+package lark_util
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+/** -------------------------------------------------云空间-------------------------------------------------------------------- **/
+
+const (
+	FileTypeFolder   = "folder"
+	FileTypeSheet    = "sheet"
+	FileTypeDoc      = "doc"
+	FileTypeDocx     = "docx"
+	FileTypeBitable  = "bitable"
+	FileTypeMindnote = "mindnote"
+	FileTypeFile     = "file"
+	FileTypeShortcut = "shortcut"
+)
+
+type (
+	DriveFile struct {
+		Token        string `json:"token"`
+		Name         string `json:"name"`
+		Type         string `json:"type"` // 见FileType开头的常量
+		ParentToken  string `json:"parent_token"`
+		Url          string `json:"url"`
+		CreatedTime  string `json:"created_time"`
+		ModifiedTime string `json:"modified_time"`
+		OwnerId      string `json:"owner_id"`
+	}
+	RootFolderMeta struct {
+		Token  string `json:"token"` // 根文件夹的folderToken
+		Id     string `json:"id"`
+		UserId string `json:"user_id"`
+	}
+	ListFolderReq struct {
+		FolderToken string // 为空时列出根目录
+		PageSize    int    // 默认50,最大200
+		PageToken   string
+		OrderBy     string // EditedTime 或 CreatedTime
+		Direction   string // ASC 或 DESC
+	}
+)
+
+// CreateFolder 在folderToken下新建文件夹,返回新文件夹的token和链接
+func (l *LarkU) CreateFolder(folderToken, name string) (token, folderUrl string, err error) {
+	httpCode, respBody, err := l.LarkPost("/open-apis/drive/v1/files/create_folder", map[string]interface{}{
+		"name":         name,
+		"folder_token": folderToken,
+	})
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type CreateFolderResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data struct {
+			Token string `json:"token,omitempty"`
+			Url   string `json:"url,omitempty"`
+		}
+	}
+	m := new(CreateFolderResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	token, folderUrl = m.Data.Token, m.Data.Url
+	return
+}
+
+func (req *ListFolderReq) query() url.Values {
+	var values = url.Values{}
+	if req.FolderToken != "" {
+		values.Set("folder_token", req.FolderToken)
+	}
+	if req.PageSize > 0 {
+		values.Set("page_size", strconv.Itoa(req.PageSize))
+	}
+	if req.OrderBy != "" {
+		values.Set("order_by", req.OrderBy)
+	}
+	if req.Direction != "" {
+		values.Set("direction", req.Direction)
+	}
+	return values
+}
+
+// ListFolder 列出文件夹下的一页文件,hasMore为true时用nextPageToken继续获取
+func (l *LarkU) ListFolder(req *ListFolderReq) (files []*DriveFile, nextPageToken string, hasMore bool, err error) {
+	return larkGetPage[*DriveFile](l, "/open-apis/drive/v1/files", req.query(), "files", req.PageToken)
+}
+
+// IterFolder 逐页遍历文件夹下的文件,req.PageToken会被忽略
+func (l *LarkU) IterFolder(ctx context.Context, req *ListFolderReq) *Pager[*DriveFile] {
+	return larkGetPager[*DriveFile](ctx, l, "/open-apis/drive/v1/files", req.query(), "files")
+}
+
+// ListFolderAll 列出文件夹下的所有文件
+func (l *LarkU) ListFolderAll(folderToken string) (files []*DriveFile, err error) {
+	return l.IterFolder(context.Background(), &ListFolderReq{FolderToken: folderToken, PageSize: 200}).All()
+}
+
+// GetRootFolderMeta 获取根文件夹(我的空间)的元数据
+func (l *LarkU) GetRootFolderMeta() (meta *RootFolderMeta, err error) {
+	httpCode, respBody, err := l.LarkGet("/open-apis/drive/explorer/v2/root_folder/meta", nil)
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type GetRootFolderMetaResp struct {
+		Code int32           `json:"code,omitempty"`
+		Msg  string          `json:"msg,omitempty"`
+		Data *RootFolderMeta `json:"data,omitempty"`
+	}
+	m := new(GetRootFolderMetaResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	meta = m.Data
+	return
+}
+
+// MoveFile 把文件移动到folderToken下,fileType见FileType开头的常量;移动文件夹是异步的,返回taskId
+func (l *LarkU) MoveFile(fileToken, fileType, folderToken string) (taskId string, err error) {
+	httpCode, respBody, err := l.LarkPost("/open-apis/drive/v1/files/"+fileToken+"/move", map[string]interface{}{
+		"type":         fileType,
+		"folder_token": folderToken,
+	})
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type MoveFileResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data struct {
+			TaskId string `json:"task_id,omitempty"`
+		}
+	}
+	m := new(MoveFileResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	taskId = m.Data.TaskId
+	return
+}
+
+// CopyFile 把文件复制到folderToken下并命名为name,不支持复制文件夹
+func (l *LarkU) CopyFile(fileToken, fileType, folderToken, name string) (file *DriveFile, err error) {
+	httpCode, respBody, err := l.LarkPost("/open-apis/drive/v1/files/"+fileToken+"/copy", map[string]interface{}{
+		"name":         name,
+		"type":         fileType,
+		"folder_token": folderToken,
+	})
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type CopyFileResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data struct {
+			File *DriveFile `json:"file,omitempty"`
+		}
+	}
+	m := new(CopyFileResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	file = m.Data.File
+	return
+}
+
+// DeleteFile 删除文件,删除文件夹是异步的,返回taskId
+func (l *LarkU) DeleteFile(fileToken, fileType string) (taskId string, err error) {
+	httpCode, respBody, err := l.LarkDelete("/open-apis/drive/v1/files/"+fileToken+"?"+url.Values{"type": {fileType}}.Encode(), map[string]interface{}{})
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type DeleteFileResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data struct {
+			TaskId string `json:"task_id,omitempty"`
+		}
+	}
+	m := new(DeleteFileResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	taskId = m.Data.TaskId
+	return
+}
+
+// FindFiles 在文件夹下按名称查找文件,recursive为true时会进入子文件夹;fileType为空时不限类型
+func (l *LarkU) FindFiles(folderToken, name, fileType string, recursive bool) (files []*DriveFile, err error) {
+	children, err := l.ListFolderAll(folderToken)
+	if err != nil {
+		return
+	}
+	for _, f := range children {
+		if f.Name == name && (fileType == "" || f.Type == fileType) {
+			files = append(files, f)
+		}
+		if recursive && f.Type == FileTypeFolder {
+			found, e := l.FindFiles(f.Token, name, fileType, recursive)
+			if e != nil {
+				return nil, e
+			}
+			files = append(files, found...)
+		}
+	}
+	return
+}
+
+// EnsureFolderPath 在folderToken下逐级查找名为names的文件夹,不存在时创建,返回最后一级文件夹的token.
+// 如 EnsureFolderPath(root, "报表", "2026-10") 用于按月归档生成的表格
+func (l *LarkU) EnsureFolderPath(folderToken string, names ...string) (token string, err error) {
+	token = folderToken
+	for _, name := range names {
+		found, e := l.FindFiles(token, name, FileTypeFolder, false)
+		if e != nil {
+			return "", e
+		}
+		if len(found) > 0 {
+			token = found[0].Token
+			continue
+		}
+		if token, _, err = l.CreateFolder(token, name); err != nil {
+			err = errors.Wrapf(err, "create folder %q", name)
+			return
+		}
+	}
+	return
+}
+
+/** -------------------------------------------------云空间-------------------------------------------------------------------- **/
@@ -0,0 +1,197 @@
+package lark_util
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+/** -------------------------------------------------导出备份----------------------------------------------------------------- **/
+
+type DumpExcelReq struct {
+	ExcelToken           string
+	ChunkRows            int    // 每次读取的行数,默认500;单次响应不能超过10MB,列多或内容长时需要调小
+	ValueRenderOption    string // 见ValueRender开头的常量,备份时一般用ValueRenderFormula保留公式
+	DateTimeRenderOption string // 为FormattedString时日期按格式返回字符串
+}
+
+func (req *DumpExcelReq) chunkRows() int {
+	if req.ChunkRows <= 0 {
+		return 500
+	}
+	return req.ChunkRows
+}
+
+// DumpExcelCSV 把表格中的每个sheet分别导出为csv.open为每个sheet返回要写入的Writer,返回nil时跳过该sheet;
+// 数据按ChunkRows分块读取并写出,不会一次性加载整个sheet,末尾的空行会被忽略
+func (l *LarkU) DumpExcelCSV(ctx context.Context, req *DumpExcelReq, open func(sheet *Sheets) (io.Writer, error)) (err error) {
+	info, err := l.GetExcelInfo(req.ExcelToken, "", "")
+	if err != nil {
+		return
+	}
+	for _, sheet := range info.Sheets {
+		if sheet.BlockInfo.BlockToken != "" {
+			continue
+		}
+		w, e := open(sheet)
+		if e != nil {
+			return errors.Wrapf(e, "open writer of sheet %s", sheet.Title)
+		}
+		if w == nil {
+			continue
+		}
+		cw := csv.NewWriter(w)
+		err = l.walkSheetRows(ctx, req, sheet, func(row []interface{}) error {
+			record := make([]string, len(row))
+			for i, v := range row {
+				record[i] = cellText(v)
+			}
+			return cw.Write(record)
+		})
+		if err != nil {
+			return errors.Wrapf(err, "dump sheet %s", sheet.Title)
+		}
+		cw.Flush()
+		if err = cw.Error(); err != nil {
+			return errors.Wrapf(err, "dump sheet %s", sheet.Title)
+		}
+	}
+	return
+}
+
+// DumpExcelJSON 把整个表格导出为一个json文档,包括表格属性以及每个sheet的属性(合并单元格、冻结行列、保护范围)和数据:
+//
+//	{"spreadsheetToken":"","properties":{},"sheets":[{"properties":{},"rows":[[]]}]}
+//
+// 数据按ChunkRows分块读取并流式写入w,末尾的空行会被忽略.开放接口不提供单元格样式的读取,因此不包含样式
+func (l *LarkU) DumpExcelJSON(ctx context.Context, req *DumpExcelReq, w io.Writer) (err error) {
+	info, err := l.GetExcelInfo(req.ExcelToken, "protectedRange", "")
+	if err != nil {
+		return
+	}
+	properties, _ := json.Marshal(info.Properties)
+	if _, err = fmt.Fprintf(w, `{"spreadsheetToken":%q,"properties":%s,"sheets":[`, info.SpreadsheetToken, properties); err != nil {
+		return
+	}
+	for i, sheet := range info.Sheets {
+		sheetProperties, _ := json.Marshal(sheet)
+		if i > 0 {
+			if _, err = io.WriteString(w, ","); err != nil {
+				return
+			}
+		}
+		if _, err = fmt.Fprintf(w, `{"properties":%s,"rows":[`, sheetProperties); err != nil {
+			return
+		}
+		if sheet.BlockInfo.BlockToken == "" {
+			first := true
+			err = l.walkSheetRows(ctx, req, sheet, func(row []interface{}) error {
+				b, e := json.Marshal(row)
+				if e != nil {
+					return e
+				}
+				if !first {
+					b = append([]byte(","), b...)
+				}
+				first = false
+				_, e = w.Write(b)
+				return e
+			})
+			if err != nil {
+				return errors.Wrapf(err, "dump sheet %s", sheet.Title)
+			}
+		}
+		if _, err = io.WriteString(w, "]}"); err != nil {
+			return
+		}
+	}
+	_, err = io.WriteString(w, "]}")
+	return
+}
+
+// walkSheetRows 按块读取sheet的每一行并依次回调fn.空行先暂存,后面出现非空行时才补上,从而忽略末尾的空行
+func (l *LarkU) walkSheetRows(ctx context.Context, req *DumpExcelReq, sheet *Sheets, fn func(row []interface{}) error) (err error) {
+	if sheet.RowCount <= 0 || sheet.ColumnCount <= 0 {
+		return
+	}
+	chunk := req.chunkRows()
+	pendingEmpty := 0
+	for start := 0; start < sheet.RowCount; start += chunk {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		end := start + chunk
+		if end > sheet.RowCount {
+			end = sheet.RowCount
+		}
+		valueRange, e := l.GetCellValues(req.ExcelToken, SheetRange(sheet.SheetId, 0, start, sheet.ColumnCount-1, end-1), req.ValueRenderOption, req.DateTimeRenderOption)
+		if e != nil {
+			return errors.Wrapf(e, "read rows %d-%d", start+1, end)
+		}
+		for i := 0; i < end-start; i++ {
+			var row []interface{}
+			if i < len(valueRange.Values) {
+				row = valueRange.Values[i]
+			}
+			if isEmptyRow(row) {
+				pendingEmpty++
+				continue
+			}
+			for ; pendingEmpty > 0; pendingEmpty-- {
+				if err = fn(make([]interface{}, sheet.ColumnCount)); err != nil {
+					return
+				}
+			}
+			if err = fn(row); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+func isEmptyRow(row []interface{}) bool {
+	for _, v := range row {
+		if cellText(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// cellText 把读取到的单元格值转换为文本,富文本(链接、提及等)取其中的text拼接
+func cellText(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(t)
+	case map[string]interface{}:
+		if text, ok := t["text"].(string); ok {
+			return text
+		}
+		if link, ok := t["link"].(string); ok {
+			return link
+		}
+	case []interface{}:
+		var b strings.Builder
+		for _, segment := range t {
+			b.WriteString(cellText(segment))
+		}
+		return b.String()
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+/** -------------------------------------------------导出备份----------------------------------------------------------------- **/
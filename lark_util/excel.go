@@ -136,6 +136,7 @@ type UpdateExcelReq struct {
 
 // UpdateExcel 更新表格属性,暂时只有更新标题
 func (l *LarkU) UpdateExcel(req *UpdateExcelReq) (err error) {
+	defer l.InvalidateExcelInfo(req.ExcelToken)
 	httpCode, respBody, err := l.LarkPut("/open-apis/sheets/v2/spreadsheets/"+req.ExcelToken+"/properties", map[string]interface{}{
 		"properties": req.Properties,
 	})
@@ -156,6 +157,7 @@ func (l *LarkU) UpdateExcel(req *UpdateExcelReq) (err error) {
 type (
 	HandleSheetReq struct {
 		ExcelToken string
+		UserIdType string                `json:"-"` // HandleSheetProtect.UserIDs的类型,可选open_id、union_id,为空时飞书会忽略UserIDs
 		Requests   []*HandleSheetRequest `json:"requests,omitempty"`
 	}
 	AddSheet struct {
@@ -201,7 +203,12 @@ type (
 
 // HandleSheet 操作工作表,包括增删复制
 func (l *LarkU) HandleSheet(req *HandleSheetReq) (err error) {
-	httpCode, respBody, err := l.LarkPost("/open-apis/sheets/v2/spreadsheets/"+req.ExcelToken+"/sheets/sheets_batch_update", map[string]interface{}{
+	defer l.InvalidateExcelInfo(req.ExcelToken)
+	path := "/open-apis/sheets/v2/spreadsheets/" + req.ExcelToken + "/sheets/sheets_batch_update"
+	if req.UserIdType != "" {
+		path += "?" + url.Values{"user_id_type": {req.UserIdType}}.Encode()
+	}
+	httpCode, respBody, err := l.LarkPost(path, map[string]interface{}{
 		"requests": req.Requests,
 	})
 	if err != nil {
@@ -210,6 +217,16 @@ func (l *LarkU) HandleSheet(req *HandleSheetReq) (err error) {
 	}
 	if httpCode != http.StatusOK {
 		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type HandleSheetResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+	}
+	m := new(HandleSheetResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
 	}
 	return
 }
@@ -237,6 +254,7 @@ const (
 
 // AddDimension 增加行列
 func (l *LarkU) AddDimension(req *AddDimensionReq) (err error) {
+	defer l.InvalidateExcelInfo(req.ExcelToken)
 	httpCode, respBody, err := l.LarkPost("/open-apis/sheets/v2/spreadsheets/"+req.ExcelToken+"/dimension_range", map[string]interface{}{
 		"dimension": req.Dimension,
 	})
@@ -272,6 +290,7 @@ const (
 // InsertDimension 插入行列 用于根据 spreadsheetToken 和维度信息 插入空行/列。
 // 如 startIndex=3,endIndex=7,则从第 4 行开始开始插入行列,一直到第 7 行,共插入 4 行;单次操作不超过5000行或列
 func (l *LarkU) InsertDimension(req *InsertDimensionReq) (err error) {
+	defer l.InvalidateExcelInfo(req.ExcelToken)
 	httpCode, respBody, err := l.LarkPost("/open-apis/sheets/v2/spreadsheets/"+req.ExcelToken+"/dimension_range", map[string]interface{}{
 		"dimension": req.Dimension,
 	})
@@ -306,7 +325,8 @@ type (
 // UpdateDimension 更新行列
 func (l *LarkU) UpdateDimension(req *UpdateDimensionReq) (err error) {
 	httpCode, respBody, err := l.LarkPut("/open-apis/sheets/v2/spreadsheets/"+req.ExcelToken+"/dimension_range", map[string]interface{}{
-		"dimension": req.Dimension,
+		"dimension":           req.Dimension,
+		"dimensionProperties": req.DimensionProperties,
 	})
 	if err != nil {
 		err = errors.Errorf("http error: %+v", err)
@@ -363,6 +383,7 @@ type (
 
 // DeleteDimension 删除行列
 func (l *LarkU) DelDimension(req *DelDimensionReq) (err error) {
+	defer l.InvalidateExcelInfo(req.ExcelToken)
 	httpCode, respBody, err := l.LarkDelete("/open-apis/sheets/v2/spreadsheets/"+req.ExcelToken+"/dimension_range", map[string]interface{}{
 		"dimension": req.Dimension,
 	})
@@ -393,6 +414,7 @@ type (
 
 // InsertValueToCell 根据 spreadsheetToken 和 range 向范围之前增加相应数据的行和相应的数据,相当于数组的插入操作;单次写入不超过5000行,100列,每个格子不超过5万字符
 func (l *LarkU) InsertValueToCell(req *InsertValueToCellReq) (err error) {
+	defer l.InvalidateExcelInfo(req.ExcelToken)
 	httpCode, respBody, err := l.LarkPost("/open-apis/sheets/v2/spreadsheets/"+req.ExcelToken+"/values_prepend", map[string]interface{}{
 		"valueRange": req.ValueRange,
 	})
@@ -406,6 +428,145 @@ func (l *LarkU) InsertValueToCell(req *InsertValueToCellReq) (err error) {
 	return
 }
 
+type WriteValueToCellReq struct {
+	ExcelToken string
+	ValueRange InsertValueToCellValueRange `json:"valueRange"`
+}
+
+// WriteValueToCell 根据 spreadsheetToken 和 range 覆盖写入数据,不会插入行;单次写入不超过5000行,100列,每个格子不超过5万字符
+func (l *LarkU) WriteValueToCell(req *WriteValueToCellReq) (err error) {
+	_, err = l.writeValueToCell(req)
+	return
+}
+
+// writeValueToCell 同WriteValueToCell,同时返回写入后的表格版本号
+func (l *LarkU) writeValueToCell(req *WriteValueToCellReq) (revision int, err error) {
+	defer l.InvalidateExcelInfo(req.ExcelToken)
+	httpCode, respBody, err := l.LarkPut("/open-apis/sheets/v2/spreadsheets/"+req.ExcelToken+"/values", map[string]interface{}{
+		"valueRange": req.ValueRange,
+	})
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type WriteValueToCellResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data struct {
+			Revision int `json:"revision"`
+		}
+	}
+	m := new(WriteValueToCellResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	revision = m.Data.Revision
+	return
+}
+
+type BatchWriteValueToCellReq struct {
+	ExcelToken  string
+	ValueRanges []InsertValueToCellValueRange `json:"valueRanges"`
+}
+
+// BatchWriteValueToCell 一次覆盖写入多个范围,范围可以在不同的sheet中;单次写入不超过5000行,100列,每个格子不超过5万字符
+func (l *LarkU) BatchWriteValueToCell(req *BatchWriteValueToCellReq) (err error) {
+	_, err = l.batchWriteValueToCell(req)
+	return
+}
+
+// batchWriteValueToCell 同BatchWriteValueToCell,同时返回写入后的表格版本号
+func (l *LarkU) batchWriteValueToCell(req *BatchWriteValueToCellReq) (revision int, err error) {
+	defer l.InvalidateExcelInfo(req.ExcelToken)
+	httpCode, respBody, err := l.LarkPost("/open-apis/sheets/v2/spreadsheets/"+req.ExcelToken+"/values_batch_update", map[string]interface{}{
+		"valueRanges": req.ValueRanges,
+	})
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type BatchWriteValueToCellResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data struct {
+			Revision int `json:"revision"`
+		}
+	}
+	m := new(BatchWriteValueToCellResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	revision = m.Data.Revision
+	return
+}
+
+const (
+	ValueRenderToString         = "ToString"         // 返回纯文本的值(数值类型除外)
+	ValueRenderFormattedValue   = "FormattedValue"   // 计算并格式化单元格
+	ValueRenderFormula          = "Formula"          // 单元格中含有公式时返回公式本身
+	ValueRenderUnformattedValue = "UnformattedValue" // 计算但不对单元格进行格式化
+)
+
+type CellValueRange struct {
+	MajorDimension string          `json:"majorDimension"`
+	Range          string          `json:"range"`
+	Revision       int             `json:"revision"`
+	Values         [][]interface{} `json:"values"`
+}
+
+// GetCellValues 读取单个范围的数据,cellRange形如 sheetId!A1:B2;valueRenderOption见ValueRender开头的常量,
+// dateTimeRenderOption为FormattedString时日期按格式返回字符串.返回数据不超过10MB
+func (l *LarkU) GetCellValues(excelToken, cellRange, valueRenderOption, dateTimeRenderOption string) (valueRange *CellValueRange, err error) {
+	var values = url.Values{}
+	if valueRenderOption != "" {
+		values.Set("valueRenderOption", valueRenderOption)
+	}
+	if dateTimeRenderOption != "" {
+		values.Set("dateTimeRenderOption", dateTimeRenderOption)
+	}
+	httpCode, respBody, err := l.LarkGet("/open-apis/sheets/v2/spreadsheets/"+excelToken+"/values/"+url.PathEscape(cellRange), values)
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type GetCellValuesResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data struct {
+			Revision   int             `json:"revision"`
+			ValueRange *CellValueRange `json:"valueRange"`
+		}
+	}
+	m := new(GetCellValuesResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	valueRange = m.Data.ValueRange
+	if valueRange == nil {
+		valueRange = &CellValueRange{Range: cellRange, Revision: m.Data.Revision}
+	}
+	l.observeRevision(excelToken, m.Data.Revision)
+	return
+}
+
 const (
 	MergeCellTypeAll     = "MERGE_ALL"
 	MergeCellTypeRows    = "MERGE_ROWS"
@@ -414,6 +575,7 @@ const (
 
 // MergeCells 合并单元格
 func (l *LarkU) MergeCells(excelToken, sheetId, cellRange, mergeType string) (err error) {
+	defer l.InvalidateExcelInfo(excelToken)
 	if mergeType == "" {
 		mergeType = MergeCellTypeAll
 	}
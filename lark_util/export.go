@@ -0,0 +1,180 @@
+package lark_util
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+/** -------------------------------------------------导出-------------------------------------------------------------------- **/
+
+const (
+	ExportFileExtensionXlsx = "xlsx"
+	ExportFileExtensionCsv  = "csv"
+)
+
+// 导出/导入任务的状态,其余取值均为失败,原因见JobErrorMsg
+const (
+	JobStatusSuccess    = 0
+	JobStatusInit       = 1
+	JobStatusProcessing = 2
+)
+
+type (
+	CreateExportTaskReq struct {
+		FileExtension string `json:"file_extension"`   // 导出文件扩展名,xlsx 或 csv
+		Token         string `json:"token"`            // 导出文档的token,表格即spreadsheetToken
+		Type          string `json:"type"`             // 导出文档的类型,表格为sheet
+		SubId         string `json:"sub_id,omitempty"` // 导出csv时必填,为要导出的sheetId
+	}
+	ExportTaskResult struct {
+		FileExtension string `json:"file_extension"`
+		Type          string `json:"type"`
+		FileName      string `json:"file_name"`
+		FileToken     string `json:"file_token"` // 导出文件的token,用于下载
+		FileSize      int64  `json:"file_size"`
+		JobErrorMsg   string `json:"job_error_msg"`
+		JobStatus     int    `json:"job_status"` // 任务状态,见JobStatus开头的常量
+	}
+)
+
+// CreateExportTask 创建导出任务,返回用于查询任务结果的ticket
+func (l *LarkU) CreateExportTask(req *CreateExportTaskReq) (ticket string, err error) {
+	if req.Type == "" {
+		req.Type = "sheet"
+	}
+	httpCode, respBody, err := l.LarkPost("/open-apis/drive/v1/export_tasks", map[string]interface{}{
+		"file_extension": req.FileExtension,
+		"token":          req.Token,
+		"type":           req.Type,
+		"sub_id":         req.SubId,
+	})
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type CreateExportTaskResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data struct {
+			Ticket string `json:"ticket,omitempty"`
+		}
+	}
+	m := new(CreateExportTaskResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	ticket = m.Data.Ticket
+	return
+}
+
+// GetExportTask 查询导出任务结果,token为导出文档的token
+func (l *LarkU) GetExportTask(ticket, token string) (result *ExportTaskResult, err error) {
+	httpCode, respBody, err := l.LarkGet("/open-apis/drive/v1/export_tasks/"+ticket, url.Values{"token": {token}})
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type GetExportTaskResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data struct {
+			Result *ExportTaskResult `json:"result,omitempty"`
+		}
+	}
+	m := new(GetExportTaskResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	if m.Data.Result == nil {
+		err = errors.Errorf("remote service error: empty export task result | %s", string(respBody))
+		return
+	}
+	result = m.Data.Result
+	return
+}
+
+// DownloadExportFile 下载导出文件并写入w
+func (l *LarkU) DownloadExportFile(ctx context.Context, fileToken string, w io.Writer) (err error) {
+	httpCode, respBody, err := l.LarkDownload(ctx, "/open-apis/drive/v1/export_tasks/file/"+fileToken+"/download", nil, w)
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+	}
+	return
+}
+
+type ExportExcelReq struct {
+	ExcelToken    string
+	FileExtension string        // 默认xlsx;为csv时需要填SheetId,每次只能导出一个sheet
+	SheetId       string        // 导出csv时要导出的sheetId
+	Timeout       time.Duration // 整个导出流程的超时时间,默认5分钟
+}
+
+// ExportExcel 导出表格为xlsx/csv并写入w.创建导出任务后以退避的方式轮询任务状态,完成后把文件流式写入w
+func (l *LarkU) ExportExcel(ctx context.Context, req *ExportExcelReq, w io.Writer) (result *ExportTaskResult, err error) {
+	if req.FileExtension == "" {
+		req.FileExtension = ExportFileExtensionXlsx
+	}
+	if req.FileExtension == ExportFileExtensionCsv && req.SheetId == "" {
+		err = errors.New("sheet id is required when exporting csv")
+		return
+	}
+	if req.Timeout <= 0 {
+		req.Timeout = 5 * time.Minute
+	}
+	ctx, cancel := context.WithTimeout(ctx, req.Timeout)
+	defer cancel()
+
+	ticket, err := l.CreateExportTask(&CreateExportTaskReq{
+		FileExtension: req.FileExtension,
+		Token:         req.ExcelToken,
+		Type:          "sheet",
+		SubId:         req.SheetId,
+	})
+	if err != nil {
+		return
+	}
+	err = pollUntil(ctx, 500*time.Millisecond, 10*time.Second, func() (done bool, err error) {
+		result, err = l.GetExportTask(ticket, req.ExcelToken)
+		if err != nil {
+			return
+		}
+		switch result.JobStatus {
+		case JobStatusSuccess:
+			done = true
+		case JobStatusInit, JobStatusProcessing:
+		default:
+			err = errors.Errorf("export task failed: status = %d | %s", result.JobStatus, result.JobErrorMsg)
+		}
+		return
+	})
+	if err != nil {
+		err = errors.Wrapf(err, "wait export task %s", ticket)
+		return
+	}
+	err = l.DownloadExportFile(ctx, result.FileToken, w)
+	return
+}
+
+/** -------------------------------------------------导出-------------------------------------------------------------------- **/
@@ -0,0 +1,169 @@
+package lark_util
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+/** -------------------------------------------------数字格式-------------------------------------------------------------- **/
+
+// 飞书表格支持的常用数字格式,用于Style.Formatter/CellStyle.Formatter
+const (
+	FormatGeneral    = "General"  // 常规
+	FormatText       = "@"        // 纯文本
+	FormatInteger    = "0"        // 整数
+	FormatDecimal2   = "0.00"     // 两位小数
+	FormatThousands  = "#,##0"    // 千分位
+	FormatThousands2 = "#,##0.00" // 千分位两位小数
+	FormatPercent    = "0%"       // 百分比
+	FormatPercent2   = "0.00%"    // 两位小数的百分比
+	FormatScientific = "0.00E+00" // 科学计数
+
+	FormatCNY = "¥#,##0.00" // 人民币
+	FormatUSD = "$#,##0.00" // 美元
+	FormatEUR = "€#,##0.00" // 欧元
+	FormatGBP = "£#,##0.00" // 英镑
+	FormatJPY = "¥#,##0"    // 日元,无小数
+
+	FormatDateSlash     = "yyyy/MM/dd"          // 2026/10/19
+	FormatDateISO       = "yyyy-MM-dd"          // 2026-10-19
+	FormatDateCN        = "yyyy年MM月dd日"         // 2026年10月19日
+	FormatDateUS        = "MM/dd/yyyy"          // 10/19/2026
+	FormatDateEU        = "dd/MM/yyyy"          // 19/10/2026
+	FormatMonthCN       = "yyyy年MM月"            // 2026年10月
+	FormatTime          = "HH:mm:ss"            // 13:30:00
+	FormatTimeShort     = "HH:mm"               // 13:30
+	FormatTimeCN        = "HH时mm分ss秒"           // 13时30分00秒
+	FormatDateTimeSlash = "yyyy/MM/dd HH:mm:ss" // 2026/10/19 13:30:00
+	FormatDateTimeISO   = "yyyy-MM-dd HH:mm:ss" // 2026-10-19 13:30:00
+)
+
+// NumberFormat 数字格式构造器,如 NewNumberFormat().Currency("¥").Thousands().Decimals(2).String() -> ¥#,##0.00
+type NumberFormat struct {
+	decimals       int
+	thousands      bool
+	percent        bool
+	currency       string
+	currencySuffix bool
+	negativeParens bool
+}
+
+func NewNumberFormat() *NumberFormat {
+	return &NumberFormat{}
+}
+
+// Decimals 小数位数
+func (f *NumberFormat) Decimals(n int) *NumberFormat {
+	if n < 0 {
+		n = 0
+	}
+	f.decimals = n
+	return f
+}
+
+// Thousands 使用千分位分隔符
+func (f *NumberFormat) Thousands() *NumberFormat {
+	f.thousands = true
+	return f
+}
+
+// Percent 显示为百分比
+func (f *NumberFormat) Percent() *NumberFormat {
+	f.percent = true
+	return f
+}
+
+// Currency 在数字前加货币符号
+func (f *NumberFormat) Currency(symbol string) *NumberFormat {
+	f.currency, f.currencySuffix = symbol, false
+	return f
+}
+
+// CurrencySuffix 在数字后加货币符号或单位,如 元
+func (f *NumberFormat) CurrencySuffix(symbol string) *NumberFormat {
+	f.currency, f.currencySuffix = symbol, true
+	return f
+}
+
+// NegativeInParens 负数显示在括号中,如 (1,234.00)
+func (f *NumberFormat) NegativeInParens() *NumberFormat {
+	f.negativeParens = true
+	return f
+}
+
+// String 返回飞书接受的格式字符串
+func (f *NumberFormat) String() string {
+	var b strings.Builder
+	if f.currency != "" && !f.currencySuffix {
+		b.WriteString(quoteFormatLiteral(f.currency))
+	}
+	if f.thousands {
+		b.WriteString("#,##0")
+	} else {
+		b.WriteString("0")
+	}
+	if f.decimals > 0 {
+		b.WriteString(".")
+		b.WriteString(strings.Repeat("0", f.decimals))
+	}
+	if f.percent {
+		b.WriteString("%")
+	}
+	if f.currency != "" && f.currencySuffix {
+		b.WriteString(quoteFormatLiteral(f.currency))
+	}
+	if f.negativeParens {
+		positive := b.String()
+		return positive + ";(" + positive + ")"
+	}
+	return b.String()
+}
+
+// quoteFormatLiteral 单个符号直接使用,多个字符的文字需要用双引号括起来
+func quoteFormatLiteral(s string) string {
+	if len([]rune(s)) == 1 {
+		return s
+	}
+	return `"` + s + `"`
+}
+
+// ApplyColumnFormats 按列为数据范围设置数字格式,只发送一次BatchUpdateCellStyle.
+// dataRange形如 sheetId!A2:F100,formats的key为列名,如 {"C": FormatCNY, "D": FormatPercent2}
+func (l *LarkU) ApplyColumnFormats(excelToken, dataRange string, formats map[string]string) (err error) {
+	i := strings.LastIndex(dataRange, "!")
+	if i < 0 {
+		return errors.Errorf("range %q has no sheet id", dataRange)
+	}
+	sheetId := dataRange[:i]
+	startCol, startRow, endCol, endRow, err := parseRangeBounds(dataRange)
+	if err != nil {
+		return
+	}
+	columns := make([]string, 0, len(formats))
+	for column := range formats {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	data := make([]Data, 0, len(formats))
+	for _, column := range columns {
+		col, e := ColumnIndex(column)
+		if e != nil {
+			return e
+		}
+		if col < startCol || col > endCol {
+			return errors.Errorf("column %s out of range %s", column, dataRange)
+		}
+		data = append(data, Data{
+			Ranges: []string{SheetRange(sheetId, col, startRow, col, endRow)},
+			Style:  Style{Formatter: formats[column]},
+		})
+	}
+	if len(data) == 0 {
+		return
+	}
+	return l.BatchUpdateCellStyle(&BatchUpdateCellStyleReq{ExcelToken: excelToken, Data: mergeStyleData(data)})
+}
+
+/** -------------------------------------------------数字格式-------------------------------------------------------------- **/
@@ -0,0 +1,244 @@
+package lark_util
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+/** -------------------------------------------------用户组-------------------------------------------------------------------- **/
+
+// 用户组类型
+const (
+	GroupTypeAssign  = 1 // 普通用户组,成员由管理员指定
+	GroupTypeDynamic = 2 // 动态用户组,成员按规则自动计算
+)
+
+// 用户组成员类型
+const (
+	GroupMemberTypeUser       = "user"
+	GroupMemberTypeDepartment = "department"
+)
+
+// 邮件组成员类型
+const (
+	MailGroupMemberTypeUser       = "USER"
+	MailGroupMemberTypeDepartment = "DEPARTMENT"
+	MailGroupMemberTypeCompany    = "COMPANY"
+	MailGroupMemberTypeExternal   = "EXTERNAL_USER"
+	MailGroupMemberTypeMailGroup  = "MAIL_GROUP"
+	MailGroupMemberTypeOther      = "OTHER_MEMBER"
+)
+
+type (
+	Group struct {
+		Id                    string `json:"id"`
+		Name                  string `json:"name"`
+		Description           string `json:"description,omitempty"`
+		MemberUserCount       int    `json:"member_user_count,omitempty"`
+		MemberDepartmentCount int    `json:"member_department_count,omitempty"`
+		Type                  int    `json:"type,omitempty"` // 见GroupType开头的常量
+	}
+	GroupMember struct {
+		MemberId     string `json:"member_id"`
+		MemberType   string `json:"member_type"`    // 见GroupMemberType开头的常量
+		MemberIdType string `json:"member_id_type"` // 与查询时的member_id_type相同
+	}
+	MailGroupMember struct {
+		MemberId     string `json:"member_id"`
+		Email        string `json:"email,omitempty"`         // 外部用户、邮件组、其他成员时有值
+		UserId       string `json:"user_id,omitempty"`       // 类型由user_id_type决定
+		DepartmentId string `json:"department_id,omitempty"` // 类型由department_id_type决定
+		Type         string `json:"type"`                    // 见MailGroupMemberType开头的常量
+	}
+	// ExpandUserIdsReq 把用户组、部门、邮件组和邮箱展开为用户id,用于HandleSheetProtect.UserIDs或文档权限
+	ExpandUserIdsReq struct {
+		GroupIds         []string
+		DepartmentIds    []string // 包含所有下级部门的用户,类型由DepartmentIdType决定
+		MailGroupIds     []string // 邮件组的id或邮箱地址,嵌套的邮件组会继续展开
+		Emails           []string // 找不到对应用户的邮箱会被忽略
+		UserIds          []string // 原样加入结果
+		UserIdType       string   // 见UserIdType开头的常量,默认open_id
+		DepartmentIdType string   // 见DepartmentIdType开头的常量,默认open_department_id
+	}
+)
+
+// IterGroups 逐页遍历用户组,groupType见GroupType开头的常量
+func (l *LarkU) IterGroups(ctx context.Context, groupType int) *Pager[*Group] {
+	values := url.Values{"page_size": {"100"}}
+	if groupType > 0 {
+		values.Set("type", strconv.Itoa(groupType))
+	}
+	return larkGetPager[*Group](ctx, l, "/open-apis/contact/v3/group/simplelist", values, "grouplist")
+}
+
+// ListGroups 列出所有用户组
+func (l *LarkU) ListGroups(groupType int) (groups []*Group, err error) {
+	return l.IterGroups(context.Background(), groupType).All()
+}
+
+// GetGroup 获取用户组信息
+func (l *LarkU) GetGroup(groupId string) (group *Group, err error) {
+	httpCode, respBody, err := l.LarkGet("/open-apis/contact/v3/group/"+url.PathEscape(groupId), nil)
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type GetGroupResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data struct {
+			Group *Group `json:"group,omitempty"`
+		}
+	}
+	m := new(GetGroupResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	group = m.Data.Group
+	return
+}
+
+// IterGroupMembers 逐页遍历用户组的成员,memberType见GroupMemberType开头的常量;
+// memberIdType为用户成员时见UserIdType开头的常量,为部门成员时见DepartmentIdType开头的常量
+func (l *LarkU) IterGroupMembers(ctx context.Context, groupId, memberType, memberIdType string) *Pager[*GroupMember] {
+	values := url.Values{"page_size": {"100"}, "member_type": {memberType}}
+	if memberIdType != "" {
+		values.Set("member_id_type", memberIdType)
+	}
+	return larkGetPager[*GroupMember](ctx, l, "/open-apis/contact/v3/group/"+url.PathEscape(groupId)+"/member/simplelist", values, "memberlist")
+}
+
+// ListGroupMembers 列出用户组的所有成员
+func (l *LarkU) ListGroupMembers(groupId, memberType, memberIdType string) (members []*GroupMember, err error) {
+	return l.IterGroupMembers(context.Background(), groupId, memberType, memberIdType).All()
+}
+
+// IterMailGroupMembers 逐页遍历邮件组的成员,mailGroupId可以是邮件组的id或邮箱地址
+func (l *LarkU) IterMailGroupMembers(ctx context.Context, mailGroupId, userIdType, departmentIdType string) *Pager[*MailGroupMember] {
+	values := contactIdQuery(userIdType, departmentIdType)
+	values.Set("page_size", "200")
+	return larkGetPager[*MailGroupMember](ctx, l, "/open-apis/mail/v1/mailgroups/"+url.PathEscape(mailGroupId)+"/members", values, "items")
+}
+
+// ExpandUserIds 把用户组、部门、邮件组和邮箱展开为去重后的用户id列表,按首次出现的顺序排列
+func (l *LarkU) ExpandUserIds(ctx context.Context, req *ExpandUserIdsReq) (userIds []string, err error) {
+	departmentIdType := req.DepartmentIdType
+	if departmentIdType == "" {
+		departmentIdType = DepartmentIdTypeOpenDepartmentId
+	}
+	seen := make(map[string]bool)
+	add := func(ids ...string) {
+		for _, id := range ids {
+			if id != "" && !seen[id] {
+				seen[id] = true
+				userIds = append(userIds, id)
+			}
+		}
+	}
+	add(req.UserIds...)
+
+	departmentIds := append([]string(nil), req.DepartmentIds...)
+	emails := append([]string(nil), req.Emails...)
+	for _, groupId := range req.GroupIds {
+		p := l.IterGroupMembers(ctx, groupId, GroupMemberTypeUser, req.UserIdType)
+		for p.Next() {
+			add(p.Value().MemberId)
+		}
+		if err = p.Err(); err != nil {
+			return nil, errors.Wrapf(err, "list users of group %s", groupId)
+		}
+		p = l.IterGroupMembers(ctx, groupId, GroupMemberTypeDepartment, departmentIdType)
+		for p.Next() {
+			departmentIds = append(departmentIds, p.Value().MemberId)
+		}
+		if err = p.Err(); err != nil {
+			return nil, errors.Wrapf(err, "list departments of group %s", groupId)
+		}
+	}
+
+	mailGroups := append([]string(nil), req.MailGroupIds...)
+	seenMailGroups := make(map[string]bool)
+	for len(mailGroups) > 0 {
+		mailGroupId := mailGroups[0]
+		mailGroups = mailGroups[1:]
+		if seenMailGroups[mailGroupId] {
+			continue
+		}
+		seenMailGroups[mailGroupId] = true
+		p := l.IterMailGroupMembers(ctx, mailGroupId, req.UserIdType, departmentIdType)
+		for p.Next() {
+			member := p.Value()
+			switch member.Type {
+			case MailGroupMemberTypeUser:
+				add(member.UserId)
+			case MailGroupMemberTypeDepartment:
+				departmentIds = append(departmentIds, member.DepartmentId)
+			case MailGroupMemberTypeCompany:
+				departmentIds = append(departmentIds, RootDepartmentId)
+			case MailGroupMemberTypeMailGroup:
+				mailGroups = append(mailGroups, member.Email)
+			default:
+				if member.Email != "" {
+					emails = append(emails, member.Email)
+				}
+			}
+		}
+		if err = p.Err(); err != nil {
+			return nil, errors.Wrapf(err, "list members of mail group %s", mailGroupId)
+		}
+	}
+
+	seenDepartments := make(map[string]bool)
+	for _, departmentId := range departmentIds {
+		if departmentId == "" || seenDepartments[departmentId] {
+			continue
+		}
+		subtree := []string{departmentId}
+		p := l.IterChildDepartments(ctx, &ListDepartmentReq{DepartmentId: departmentId, DepartmentIdType: departmentIdType, FetchChild: true, PageSize: 50})
+		for p.Next() {
+			subtree = append(subtree, p.Value().Id(departmentIdType))
+		}
+		if err = p.Err(); err != nil {
+			return nil, errors.Wrapf(err, "list sub departments of %s", departmentId)
+		}
+		for _, id := range subtree {
+			if seenDepartments[id] {
+				continue
+			}
+			seenDepartments[id] = true
+			users := l.IterDepartmentUsers(ctx, &ListDepartmentReq{DepartmentId: id, UserIdType: req.UserIdType, DepartmentIdType: departmentIdType, PageSize: 50})
+			for users.Next() {
+				add(users.Value().Id(req.UserIdType))
+			}
+			if err = users.Err(); err != nil {
+				return nil, errors.Wrapf(err, "list users of department %s", id)
+			}
+		}
+	}
+
+	if len(emails) > 0 {
+		results, e := l.ResolveUserIds(&ResolveUserIdsReq{Emails: emails, UserIdType: req.UserIdType})
+		if e != nil {
+			return nil, errors.Wrap(e, "resolve emails")
+		}
+		for _, email := range emails {
+			if r := results[email]; r.Found {
+				add(r.UserId)
+			}
+		}
+	}
+	return
+}
+
+/** -------------------------------------------------用户组-------------------------------------------------------------------- **/
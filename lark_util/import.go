@@ -0,0 +1,240 @@
+package lark_util
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+/** -------------------------------------------------导入-------------------------------------------------------------------- **/
+
+type (
+	CreateImportTaskReq struct {
+		FileExtension string           `json:"file_extension"` // 导入文件扩展名,csv、xlsx、xls
+		FileToken     string           `json:"file_token"`     // 通过UploadImportFile上传得到的文件token
+		Type          string           `json:"type"`           // 导入后的文档类型,表格为sheet
+		FileName      string           `json:"file_name"`      // 导入后的文档标题
+		Point         *ImportTaskPoint `json:"point"`
+	}
+	ImportTaskPoint struct {
+		MountType int    `json:"mount_type"` // 挂载类型,1为云空间
+		MountKey  string `json:"mount_key"`  // 挂载位置,即folderToken,同CreateExcel
+	}
+	ImportTaskResult struct {
+		Ticket      string   `json:"ticket"`
+		Type        string   `json:"type"`
+		JobStatus   int      `json:"job_status"` // 任务状态,见JobStatus开头的常量
+		JobErrorMsg string   `json:"job_error_msg"`
+		Token       string   `json:"token"` // 导入后的文档token,表格即spreadsheetToken
+		Url         string   `json:"url"`   // 导入后的文档链接
+		Extra       []string `json:"extra"`
+	}
+)
+
+// UploadImportFile 上传用于导入的文件,objType为导入后的文档类型,表格为sheet.单个文件不超过20MB
+func (l *LarkU) UploadImportFile(ctx context.Context, fileName, fileExtension, objType string, content []byte) (fileToken string, err error) {
+	extra, _ := json.Marshal(map[string]string{
+		"obj_type":       objType,
+		"file_extension": fileExtension,
+	})
+	httpCode, respBody, err := l.LarkPostMultipart(ctx, "/open-apis/drive/v1/medias/upload_all", map[string]string{
+		"file_name":   fileName,
+		"parent_type": "ccm_import_open",
+		"size":        strconv.Itoa(len(content)),
+		"extra":       string(extra),
+	}, "file", fileName, bytes.NewReader(content))
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type UploadImportFileResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data struct {
+			FileToken string `json:"file_token,omitempty"`
+		}
+	}
+	m := new(UploadImportFileResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	fileToken = m.Data.FileToken
+	return
+}
+
+// CreateImportTask 创建导入任务,返回用于查询任务结果的ticket
+func (l *LarkU) CreateImportTask(req *CreateImportTaskReq) (ticket string, err error) {
+	if req.Type == "" {
+		req.Type = "sheet"
+	}
+	httpCode, respBody, err := l.LarkPost("/open-apis/drive/v1/import_tasks", map[string]interface{}{
+		"file_extension": req.FileExtension,
+		"file_token":     req.FileToken,
+		"type":           req.Type,
+		"file_name":      req.FileName,
+		"point":          req.Point,
+	})
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type CreateImportTaskResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data struct {
+			Ticket string `json:"ticket,omitempty"`
+		}
+	}
+	m := new(CreateImportTaskResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	ticket = m.Data.Ticket
+	return
+}
+
+// GetImportTask 查询导入任务结果
+func (l *LarkU) GetImportTask(ticket string) (result *ImportTaskResult, err error) {
+	httpCode, respBody, err := l.LarkGet("/open-apis/drive/v1/import_tasks/"+ticket, nil)
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type GetImportTaskResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data struct {
+			Result *ImportTaskResult `json:"result,omitempty"`
+		}
+	}
+	m := new(GetImportTaskResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	if m.Data.Result == nil {
+		err = errors.Errorf("remote service error: empty import task result | %s", string(respBody))
+		return
+	}
+	result = m.Data.Result
+	return
+}
+
+type ImportExcelReq struct {
+	FolderToken   string        // 导入到的文件夹,同CreateExcel的folderToken
+	FileName      string        // 上传的文件名,如 partner.csv
+	FileExtension string        // 不填时取FileName的扩展名
+	Title         string        // 导入后的表格标题,不填时取去掉扩展名的FileName
+	File          io.Reader     // 文件内容,不超过20MB
+	Timeout       time.Duration // 整个导入流程的超时时间,默认5分钟
+}
+
+// ImportExcel 把csv/xlsx文件导入为新的表格.上传文件并创建导入任务后以退避的方式轮询任务状态,完成后返回新表格的token和链接
+func (l *LarkU) ImportExcel(ctx context.Context, req *ImportExcelReq) (spreadsheetToken, spreadsheetUrl string, err error) {
+	ext := filepath.Ext(req.FileName)
+	if req.FileExtension == "" {
+		req.FileExtension = strings.TrimPrefix(ext, ".")
+	}
+	if req.FileExtension == "" {
+		err = errors.Errorf("unknown file extension of %q", req.FileName)
+		return
+	}
+	if req.Title == "" {
+		req.Title = strings.TrimSuffix(req.FileName, ext)
+	}
+	if req.Timeout <= 0 {
+		req.Timeout = 5 * time.Minute
+	}
+	ctx, cancel := context.WithTimeout(ctx, req.Timeout)
+	defer cancel()
+
+	content, err := io.ReadAll(io.LimitReader(req.File, MaxUploadAllSize+1))
+	if err != nil {
+		err = errors.Wrap(err, "read import file")
+		return
+	}
+	if len(content) > MaxUploadAllSize {
+		err = errors.Errorf("import file %q exceeds 20MB", req.FileName)
+		return
+	}
+	fileToken, err := l.UploadImportFile(ctx, req.FileName, req.FileExtension, "sheet", content)
+	if err != nil {
+		return
+	}
+	ticket, err := l.CreateImportTask(&CreateImportTaskReq{
+		FileExtension: req.FileExtension,
+		FileToken:     fileToken,
+		Type:          "sheet",
+		FileName:      req.Title,
+		Point: &ImportTaskPoint{
+			MountType: 1,
+			MountKey:  req.FolderToken,
+		},
+	})
+	if err != nil {
+		return
+	}
+	var result *ImportTaskResult
+	err = pollUntil(ctx, 500*time.Millisecond, 10*time.Second, func() (done bool, err error) {
+		result, err = l.GetImportTask(ticket)
+		if err != nil {
+			return
+		}
+		switch result.JobStatus {
+		case JobStatusSuccess:
+			done = true
+		case JobStatusInit, JobStatusProcessing:
+		default:
+			err = errors.Errorf("import task failed: status = %d | %s", result.JobStatus, result.JobErrorMsg)
+		}
+		return
+	})
+	if err != nil {
+		err = errors.Wrapf(err, "wait import task %s", ticket)
+		return
+	}
+	spreadsheetToken, spreadsheetUrl = result.Token, result.Url
+	return
+}
+
+// ImportExcelFile 把本地的csv/xlsx文件导入为新的表格,标题为去掉扩展名的文件名
+func (l *LarkU) ImportExcelFile(ctx context.Context, folderToken, path string) (spreadsheetToken, spreadsheetUrl string, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	return l.ImportExcel(ctx, &ImportExcelReq{
+		FolderToken: folderToken,
+		FileName:    filepath.Base(path),
+		File:        f,
+	})
+}
+
+/** -------------------------------------------------导入-------------------------------------------------------------------- **/
@@ -0,0 +1,411 @@
+package lark_util
+
+import (
+	"encoding/json"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+/** -------------------------------------------------消息-------------------------------------------------------------------- **/
+
+// 接收者id类型
+const (
+	ReceiveIdTypeOpenId  = "open_id"
+	ReceiveIdTypeUserId  = "user_id"
+	ReceiveIdTypeUnionId = "union_id"
+	ReceiveIdTypeEmail   = "email"
+	ReceiveIdTypeChatId  = "chat_id"
+)
+
+// 消息类型
+const (
+	MsgTypeText        = "text"
+	MsgTypePost        = "post"
+	MsgTypeImage       = "image"
+	MsgTypeFile        = "file"
+	MsgTypeShareChat   = "share_chat"
+	MsgTypeShareUser   = "share_user"
+	MsgTypeInteractive = "interactive"
+)
+
+// MessageContent 消息内容,序列化后作为content发送
+type MessageContent interface {
+	MsgType() string
+}
+
+type (
+	// TextContent 文本消息,@用户用AtUser、AtAll生成
+	TextContent struct {
+		Text string `json:"text"`
+	}
+	// PostContent 富文本消息,至少设置一种语言
+	PostContent struct {
+		ZhCn *PostBody `json:"zh_cn,omitempty"`
+		EnUs *PostBody `json:"en_us,omitempty"`
+		JaJp *PostBody `json:"ja_jp,omitempty"`
+	}
+	// PostBody 富文本的一种语言,Content的每个元素是一个段落
+	PostBody struct {
+		Title   string          `json:"title,omitempty"`
+		Content [][]PostElement `json:"content"`
+	}
+	// PostElement 富文本段落中的元素,用PostText、PostLink等函数创建
+	PostElement struct {
+		Tag      string   `json:"tag"`
+		Text     string   `json:"text,omitempty"`
+		Href     string   `json:"href,omitempty"`
+		UserId   string   `json:"user_id,omitempty"`
+		ImageKey string   `json:"image_key,omitempty"`
+		Style    []string `json:"style,omitempty"` // bold、underline、lineThrough、italic
+	}
+	// ImageContent 图片消息,imageKey通过上传图片获取
+	ImageContent struct {
+		ImageKey string `json:"image_key"`
+	}
+	// FileContent 文件消息,fileKey通过上传文件获取
+	FileContent struct {
+		FileKey string `json:"file_key"`
+	}
+	// ShareChatContent 分享群名片
+	ShareChatContent struct {
+		ChatId string `json:"chat_id"`
+	}
+	// ShareUserContent 分享个人名片,userId为open_id
+	ShareUserContent struct {
+		UserId string `json:"user_id"`
+	}
+	// InteractiveContent 卡片消息,Card为卡片的json结构;用CardBuilder构建的*Card可以直接作为消息内容
+	InteractiveContent struct {
+		Card interface{}
+	}
+)
+
+func (TextContent) MsgType() string        { return MsgTypeText }
+func (PostContent) MsgType() string        { return MsgTypePost }
+func (ImageContent) MsgType() string       { return MsgTypeImage }
+func (FileContent) MsgType() string        { return MsgTypeFile }
+func (ShareChatContent) MsgType() string   { return MsgTypeShareChat }
+func (ShareUserContent) MsgType() string   { return MsgTypeShareUser }
+func (InteractiveContent) MsgType() string { return MsgTypeInteractive }
+
+func (c InteractiveContent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.Card)
+}
+
+// AtUser 文本消息中@用户,userId为open_id
+func AtUser(userId string) string {
+	return `<at user_id="` + html.EscapeString(userId) + `"></at>`
+}
+
+// AtAll 文本消息中@所有人
+func AtAll() string {
+	return `<at user_id="all"></at>`
+}
+
+func PostText(text string, style ...string) PostElement {
+	return PostElement{Tag: "text", Text: text, Style: style}
+}
+
+func PostLink(text, href string) PostElement {
+	return PostElement{Tag: "a", Text: text, Href: href}
+}
+
+// PostAt 富文本中@用户,userId为open_id,all表示所有人
+func PostAt(userId string) PostElement {
+	return PostElement{Tag: "at", UserId: userId}
+}
+
+func PostImage(imageKey string) PostElement {
+	return PostElement{Tag: "img", ImageKey: imageKey}
+}
+
+type (
+	SendMessageReq struct {
+		ReceiveIdType string // 见ReceiveIdType开头的常量
+		ReceiveId     string
+		Content       MessageContent
+		Uuid          string // 不为空时,1小时内相同uuid的请求只发送一次
+	}
+	Message struct {
+		MessageId  string            `json:"message_id"`
+		RootId     string            `json:"root_id,omitempty"`
+		ParentId   string            `json:"parent_id,omitempty"`
+		ThreadId   string            `json:"thread_id,omitempty"`
+		MsgType    string            `json:"msg_type"`
+		CreateTime string            `json:"create_time"` // 毫秒级时间戳
+		UpdateTime string            `json:"update_time"`
+		Deleted    bool              `json:"deleted"`
+		Updated    bool              `json:"updated"`
+		ChatId     string            `json:"chat_id"`
+		Sender     *MessageSender    `json:"sender,omitempty"`
+		Body       *MessageBody      `json:"body,omitempty"`
+		Mentions   []*MessageMention `json:"mentions,omitempty"`
+	}
+	MessageSender struct {
+		Id         string `json:"id"`
+		IdType     string `json:"id_type"`
+		SenderType string `json:"sender_type"` // user、app
+		TenantKey  string `json:"tenant_key"`
+	}
+	MessageBody struct {
+		Content string `json:"content"` // 消息内容的json
+	}
+	MessageMention struct {
+		Key    string `json:"key"`
+		Id     string `json:"id"`
+		IdType string `json:"id_type"`
+		Name   string `json:"name"`
+	}
+)
+
+func marshalMessageContent(content MessageContent) (string, error) {
+	if content == nil {
+		return "", errors.New("message content is nil")
+	}
+	b, err := json.Marshal(content)
+	if err != nil {
+		return "", errors.Wrapf(err, "marshal %s content", content.MsgType())
+	}
+	return string(b), nil
+}
+
+// SendMessage 发送消息给用户或群
+func (l *LarkU) SendMessage(req *SendMessageReq) (msg *Message, err error) {
+	content, err := marshalMessageContent(req.Content)
+	if err != nil {
+		return
+	}
+	param := map[string]interface{}{
+		"receive_id": req.ReceiveId,
+		"msg_type":   req.Content.MsgType(),
+		"content":    content,
+	}
+	if req.Uuid != "" {
+		param["uuid"] = req.Uuid
+	}
+	httpCode, respBody, err := l.LarkPost("/open-apis/im/v1/messages?"+url.Values{"receive_id_type": {req.ReceiveIdType}}.Encode(), param)
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type SendMessageResp struct {
+		Code int32    `json:"code,omitempty"`
+		Msg  string   `json:"msg,omitempty"`
+		Data *Message `json:"data,omitempty"`
+	}
+	m := new(SendMessageResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	msg = m.Data
+	return
+}
+
+// SendToEmails 按邮箱把消息分别发给多人,返回邮箱到消息id的映射.邮箱先通过ResolveUserIds转换为open_id,
+// 找不到的邮箱不发送,其余的发送完后返回ErrUserNotFound
+func (l *LarkU) SendToEmails(emails []string, content MessageContent) (messageIds map[string]string, err error) {
+	results, err := l.ResolveUserIds(&ResolveUserIdsReq{Emails: emails, UserIdType: UserIdTypeOpenId})
+	if err != nil {
+		return
+	}
+	messageIds = make(map[string]string, len(emails))
+	var notFound []string
+	for _, email := range emails {
+		if _, ok := messageIds[email]; ok {
+			continue
+		}
+		r := results[email]
+		if !r.Found {
+			notFound = append(notFound, email)
+			continue
+		}
+		msg, e := l.SendMessage(&SendMessageReq{ReceiveIdType: ReceiveIdTypeOpenId, ReceiveId: r.UserId, Content: content})
+		if e != nil {
+			err = errors.Wrapf(e, "send to %s", email)
+			return
+		}
+		messageIds[email] = msg.MessageId
+	}
+	if len(notFound) > 0 {
+		err = errors.Wrapf(ErrUserNotFound, "emails %s", strings.Join(notFound, ", "))
+	}
+	return
+}
+
+// AtEmails 按邮箱生成文本消息中@用户的标记,多个之间用空格分隔
+func (l *LarkU) AtEmails(emails ...string) (text string, err error) {
+	results, err := l.ResolveUserIds(&ResolveUserIdsReq{Emails: emails, UserIdType: UserIdTypeOpenId})
+	if err != nil {
+		return
+	}
+	ats := make([]string, 0, len(emails))
+	for _, email := range emails {
+		r := results[email]
+		if !r.Found {
+			err = errors.Wrapf(ErrUserNotFound, "email %s", email)
+			return
+		}
+		ats = append(ats, AtUser(r.UserId))
+	}
+	text = strings.Join(ats, " ")
+	return
+}
+
+// 加急类型
+const (
+	UrgentTypeApp   = "urgent_app"   // 应用内加急
+	UrgentTypeSms   = "urgent_sms"   // 短信加急
+	UrgentTypePhone = "urgent_phone" // 电话加急
+)
+
+type ReplyMessageReq struct {
+	MessageId     string // 被回复的消息
+	Content       MessageContent
+	ReplyInThread bool   // 是否以话题形式回复,群未开启话题时会创建话题
+	Uuid          string // 不为空时,1小时内相同uuid的请求只回复一次
+}
+
+// ReplyMessage 回复指定的消息
+func (l *LarkU) ReplyMessage(req *ReplyMessageReq) (msg *Message, err error) {
+	content, err := marshalMessageContent(req.Content)
+	if err != nil {
+		return
+	}
+	param := map[string]interface{}{
+		"msg_type":        req.Content.MsgType(),
+		"content":         content,
+		"reply_in_thread": req.ReplyInThread,
+	}
+	if req.Uuid != "" {
+		param["uuid"] = req.Uuid
+	}
+	httpCode, respBody, err := l.LarkPost("/open-apis/im/v1/messages/"+url.PathEscape(req.MessageId)+"/reply", param)
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type ReplyMessageResp struct {
+		Code int32    `json:"code,omitempty"`
+		Msg  string   `json:"msg,omitempty"`
+		Data *Message `json:"data,omitempty"`
+	}
+	m := new(ReplyMessageResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	msg = m.Data
+	return
+}
+
+// PatchCard 更新已发送的卡片消息,只能更新应用发送的、14天内的卡片;
+// 卡片需要设置update_multi(CardBuilder.UpdateMulti),更新才会对所有接收者生效
+func (l *LarkU) PatchCard(messageId string, card MessageContent) (err error) {
+	if card != nil && card.MsgType() != MsgTypeInteractive {
+		return errors.Errorf("can only patch interactive message, got %s", card.MsgType())
+	}
+	content, err := marshalMessageContent(card)
+	if err != nil {
+		return
+	}
+	httpCode, respBody, err := l.LarkPatch("/open-apis/im/v1/messages/"+url.PathEscape(messageId), map[string]interface{}{
+		"content": content,
+	})
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type PatchCardResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+	}
+	m := new(PatchCardResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+	}
+	return
+}
+
+// RecallMessage 撤回应用发送的消息
+func (l *LarkU) RecallMessage(messageId string) (err error) {
+	httpCode, respBody, err := l.LarkDelete("/open-apis/im/v1/messages/"+url.PathEscape(messageId), map[string]interface{}{})
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type RecallMessageResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+	}
+	m := new(RecallMessageResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+	}
+	return
+}
+
+// UrgentMessage 对应用发送的消息加急,urgentType见UrgentType开头的常量,userIds需要是消息的接收者;
+// 返回无效的用户id,如不在会话中的用户.短信和电话加急会消耗企业的加急额度
+func (l *LarkU) UrgentMessage(messageId, urgentType, userIdType string, userIds []string) (invalidUserIds []string, err error) {
+	switch urgentType {
+	case UrgentTypeApp, UrgentTypeSms, UrgentTypePhone:
+	default:
+		return nil, errors.Errorf("unknown urgent type %q", urgentType)
+	}
+	if userIdType == "" {
+		userIdType = UserIdTypeOpenId
+	}
+	httpCode, respBody, err := l.LarkPatch("/open-apis/im/v1/messages/"+url.PathEscape(messageId)+"/"+urgentType+"?"+url.Values{"user_id_type": {userIdType}}.Encode(), map[string]interface{}{
+		"user_id_list": userIds,
+	})
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type UrgentMessageResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data struct {
+			InvalidUserIdList []string `json:"invalid_user_id_list,omitempty"`
+		}
+	}
+	m := new(UrgentMessageResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	invalidUserIds = m.Data.InvalidUserIdList
+	return
+}
+
+/** -------------------------------------------------消息-------------------------------------------------------------------- **/
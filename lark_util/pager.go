@@ -0,0 +1,148 @@
+package lark_util
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+/** -------------------------------------------------分页-------------------------------------------------------------------- **/
+
+// PageFunc 获取pageToken对应的一页数据,pageToken为空时获取第一页
+type PageFunc[T any] func(pageToken string) (items []T, nextPageToken string, hasMore bool, err error)
+
+// Pager 按page_token逐页获取列表数据的迭代器,只在需要时才请求下一页:
+//
+//	p := l.IterFolder(ctx, &ListFolderReq{FolderToken: token})
+//	for p.Next() {
+//		file := p.Value()
+//	}
+//	if err := p.Err(); err != nil {
+//	}
+type Pager[T any] struct {
+	ctx       context.Context
+	fetch     PageFunc[T]
+	maxItems  int
+	items     []T
+	pos       int
+	count     int
+	pageToken string
+	done      bool
+	value     T
+	err       error
+}
+
+// NewPager 创建迭代器,ctx取消后Next返回false,Err返回ctx的错误
+func NewPager[T any](ctx context.Context, fetch PageFunc[T]) *Pager[T] {
+	return &Pager[T]{ctx: ctx, fetch: fetch}
+}
+
+// Limit 最多返回n个元素,n<=0时不限制
+func (p *Pager[T]) Limit(n int) *Pager[T] {
+	p.maxItems = n
+	return p
+}
+
+// Next 移动到下一个元素,没有更多元素或出错时返回false
+func (p *Pager[T]) Next() bool {
+	if p.err != nil || (p.maxItems > 0 && p.count >= p.maxItems) {
+		return false
+	}
+	for p.pos >= len(p.items) {
+		if p.done {
+			return false
+		}
+		if p.err = p.ctx.Err(); p.err != nil {
+			return false
+		}
+		items, next, hasMore, err := p.fetch(p.pageToken)
+		if err != nil {
+			p.err = err
+			return false
+		}
+		p.items, p.pos, p.pageToken = items, 0, next
+		p.done = !hasMore || next == ""
+	}
+	p.value = p.items[p.pos]
+	p.pos++
+	p.count++
+	return true
+}
+
+// Value 返回当前元素,需要在Next返回true之后调用
+func (p *Pager[T]) Value() T {
+	return p.value
+}
+
+// Err 返回迭代过程中的错误
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// All 获取剩余的所有元素
+func (p *Pager[T]) All() (items []T, err error) {
+	for p.Next() {
+		items = append(items, p.Value())
+	}
+	return items, p.Err()
+}
+
+// larkGetPage 用LarkGet获取一页数据.接口返回的data中列表字段名为itemsKey,
+// 下一页的token字段为page_token或next_page_token
+func larkGetPage[T any](l *LarkU, path string, values url.Values, itemsKey, pageToken string) (items []T, nextPageToken string, hasMore bool, err error) {
+	form := url.Values{}
+	for k, v := range values {
+		form[k] = v
+	}
+	form.Del("page_token")
+	if pageToken != "" {
+		form.Set("page_token", pageToken)
+	}
+	httpCode, respBody, err := l.LarkGet(path, form)
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type LarkGetPageResp struct {
+		Code int32                      `json:"code,omitempty"`
+		Msg  string                     `json:"msg,omitempty"`
+		Data map[string]json.RawMessage `json:"data,omitempty"`
+	}
+	m := new(LarkGetPageResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	if raw, ok := m.Data[itemsKey]; ok {
+		if err = json.Unmarshal(raw, &items); err != nil {
+			err = errors.Wrapf(err, "decode %s", itemsKey)
+			return
+		}
+	}
+	for _, key := range []string{"page_token", "next_page_token"} {
+		if raw, ok := m.Data[key]; ok && nextPageToken == "" {
+			_ = json.Unmarshal(raw, &nextPageToken)
+		}
+	}
+	if raw, ok := m.Data["has_more"]; ok {
+		_ = json.Unmarshal(raw, &hasMore)
+	}
+	return
+}
+
+// larkGetPager 创建用LarkGet逐页获取数据的迭代器,values中的page_token会被忽略,从第一页开始
+func larkGetPager[T any](ctx context.Context, l *LarkU, path string, values url.Values, itemsKey string) *Pager[T] {
+	return NewPager(ctx, func(pageToken string) ([]T, string, bool, error) {
+		return larkGetPage[T](l, path, values, itemsKey, pageToken)
+	})
+}
+
+/** -------------------------------------------------分页-------------------------------------------------------------------- **/
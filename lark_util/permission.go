@@ -0,0 +1,300 @@
+package lark_util
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+/** -------------------------------------------------权限-------------------------------------------------------------------- **/
+
+// 协作者类型
+const (
+	MemberTypeEmail       = "email"
+	MemberTypeOpenId      = "openid"
+	MemberTypeUnionId     = "unionid"
+	MemberTypeUserId      = "userid"
+	MemberTypeChat        = "openchat"
+	MemberTypeDepartment  = "opendepartmentid"
+	MemberTypeGroup       = "groupid"
+	MemberTypeWikiSpaceId = "wikispaceid"
+)
+
+// 协作者权限
+const (
+	PermView       = "view"
+	PermEdit       = "edit"
+	PermFullAccess = "full_access"
+)
+
+type (
+	PermissionMember struct {
+		MemberType    string `json:"member_type"`      // 见MemberType开头的常量
+		MemberId      string `json:"member_id"`        // 与member_type对应的id,如邮箱、user_id、chat_id
+		Perm          string `json:"perm"`             // 见Perm开头的常量
+		Type          string `json:"type,omitempty"`   // 协作者的类型,user、chat、department、group
+		Name          string `json:"name,omitempty"`   // 列出协作者时返回的名称
+		Avatar        string `json:"avatar,omitempty"` // 列出协作者时返回的头像
+		ExternalLabel bool   `json:"external_label,omitempty"`
+	}
+	// PermissionPublic 文档的公共访问设置,为零值的字段不修改
+	PermissionPublic struct {
+		ExternalAccess  OptBool `json:"external_access,omitempty"`   // 是否允许分享到组织外
+		SecurityEntity  string  `json:"security_entity,omitempty"`   // 谁可以复制、创建副本、打印、下载:anyone_can_view、anyone_can_edit、only_full_access
+		CommentEntity   string  `json:"comment_entity,omitempty"`    // 谁可以评论:anyone_can_view、anyone_can_edit
+		ShareEntity     string  `json:"share_entity,omitempty"`      // 谁可以添加和管理协作者:anyone、same_tenant、only_full_access
+		LinkShareEntity string  `json:"link_share_entity,omitempty"` // 链接分享:tenant_readable、tenant_editable、anyone_readable、anyone_editable、closed
+		InviteExternal  OptBool `json:"invite_external,omitempty"`   // 是否允许非所有者邀请外部协作者
+	}
+)
+
+func permissionQuery(docType string, needNotification bool) url.Values {
+	values := url.Values{"type": {docType}}
+	if needNotification {
+		values.Set("need_notification", "true")
+	}
+	return values
+}
+
+// AddPermissionMember 为文档添加协作者,token为CreateExcel返回的spreadsheetToken等文档token,docType见FileType开头的常量
+func (l *LarkU) AddPermissionMember(token, docType string, member *PermissionMember, needNotification bool) (err error) {
+	httpCode, respBody, err := l.LarkPost("/open-apis/drive/v1/permissions/"+token+"/members?"+permissionQuery(docType, needNotification).Encode(), map[string]interface{}{
+		"member_type": member.MemberType,
+		"member_id":   member.MemberId,
+		"perm":        member.Perm,
+	})
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type AddPermissionMemberResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+	}
+	m := new(AddPermissionMemberResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+	}
+	return
+}
+
+// BatchAddPermissionMembers 一次为文档添加多个协作者
+func (l *LarkU) BatchAddPermissionMembers(token, docType string, members []*PermissionMember, needNotification bool) (err error) {
+	httpCode, respBody, err := l.LarkPost("/open-apis/drive/v1/permissions/"+token+"/members/batch_create?"+permissionQuery(docType, needNotification).Encode(), map[string]interface{}{
+		"members": members,
+	})
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type BatchAddPermissionMembersResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+	}
+	m := new(BatchAddPermissionMembersResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+	}
+	return
+}
+
+// ShareByEmails 按邮箱把文档分享给多人,perm见Perm开头的常量
+func (l *LarkU) ShareByEmails(token, docType, perm string, emails ...string) (err error) {
+	members := make([]*PermissionMember, len(emails))
+	for i, email := range emails {
+		members[i] = &PermissionMember{MemberType: MemberTypeEmail, MemberId: email, Perm: perm}
+	}
+	return l.BatchAddPermissionMembers(token, docType, members, false)
+}
+
+// ListPermissionMembers 列出文档的协作者
+func (l *LarkU) ListPermissionMembers(token, docType string) (members []*PermissionMember, err error) {
+	httpCode, respBody, err := l.LarkGet("/open-apis/drive/v1/permissions/"+token+"/members", url.Values{
+		"type":   {docType},
+		"fields": {"*"},
+	})
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type ListPermissionMembersResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data struct {
+			Items []*PermissionMember `json:"items,omitempty"`
+		}
+	}
+	m := new(ListPermissionMembersResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	members = m.Data.Items
+	return
+}
+
+// UpdatePermissionMember 修改协作者的权限
+func (l *LarkU) UpdatePermissionMember(token, docType string, member *PermissionMember, needNotification bool) (err error) {
+	httpCode, respBody, err := l.LarkPut("/open-apis/drive/v1/permissions/"+token+"/members/"+url.PathEscape(member.MemberId)+"?"+permissionQuery(docType, needNotification).Encode(), map[string]interface{}{
+		"member_type": member.MemberType,
+		"perm":        member.Perm,
+	})
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type UpdatePermissionMemberResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+	}
+	m := new(UpdatePermissionMemberResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+	}
+	return
+}
+
+// RemovePermissionMember 移除协作者
+func (l *LarkU) RemovePermissionMember(token, docType, memberType, memberId string) (err error) {
+	values := url.Values{"type": {docType}, "member_type": {memberType}}
+	httpCode, respBody, err := l.LarkDelete("/open-apis/drive/v1/permissions/"+token+"/members/"+url.PathEscape(memberId)+"?"+values.Encode(), map[string]interface{}{})
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type RemovePermissionMemberResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+	}
+	m := new(RemovePermissionMemberResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+	}
+	return
+}
+
+// GetPermissionPublic 获取文档的公共访问设置
+func (l *LarkU) GetPermissionPublic(token, docType string) (public *PermissionPublic, err error) {
+	httpCode, respBody, err := l.LarkGet("/open-apis/drive/v1/permissions/"+token+"/public", url.Values{"type": {docType}})
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type GetPermissionPublicResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data struct {
+			PermissionPublic *PermissionPublic `json:"permission_public,omitempty"`
+		}
+	}
+	m := new(GetPermissionPublicResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	public = m.Data.PermissionPublic
+	return
+}
+
+// UpdatePermissionPublic 修改文档的公共访问设置,如开启组织内获得链接可阅读:
+// &PermissionPublic{LinkShareEntity: "tenant_readable"}
+func (l *LarkU) UpdatePermissionPublic(token, docType string, public *PermissionPublic) (err error) {
+	b, _ := json.Marshal(public)
+	param := map[string]interface{}{}
+	_ = json.Unmarshal(b, &param)
+	httpCode, respBody, err := l.LarkPatch("/open-apis/drive/v1/permissions/"+token+"/public?"+url.Values{"type": {docType}}.Encode(), param)
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type UpdatePermissionPublicResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+	}
+	m := new(UpdatePermissionPublicResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+	}
+	return
+}
+
+type TransferOwnerReq struct {
+	Token            string
+	DocType          string
+	MemberType       string // 新所有者的类型,email、openid、userid
+	MemberId         string
+	NeedNotification bool
+	RemoveOldOwner   bool   // 是否移除原所有者的权限
+	OldOwnerPerm     string // 不移除时原所有者保留的权限,默认full_access
+}
+
+// TransferOwner 转移文档的所有者,应用创建的表格可以通过它转给真正的负责人
+func (l *LarkU) TransferOwner(req *TransferOwnerReq) (err error) {
+	values := permissionQuery(req.DocType, req.NeedNotification)
+	values.Set("remove_old_owner", strconv.FormatBool(req.RemoveOldOwner))
+	if req.OldOwnerPerm != "" {
+		values.Set("old_owner_perm", req.OldOwnerPerm)
+	}
+	httpCode, respBody, err := l.LarkPost("/open-apis/drive/v1/permissions/"+req.Token+"/members/transfer_owner?"+values.Encode(), map[string]interface{}{
+		"member_type": req.MemberType,
+		"member_id":   req.MemberId,
+	})
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type TransferOwnerResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+	}
+	m := new(TransferOwnerResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+	}
+	return
+}
+
+/** -------------------------------------------------权限-------------------------------------------------------------------- **/
@@ -0,0 +1,78 @@
+package lark_util
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ColumnName 把从0开始的列下标转换为列名,如 0 -> A, 26 -> AA
+func ColumnName(index int) string {
+	var b []byte
+	for index++; index > 0; index = (index - 1) / 26 {
+		b = append([]byte{byte('A' + (index-1)%26)}, b...)
+	}
+	return string(b)
+}
+
+// ColumnIndex 把列名转换为从0开始的列下标,如 A -> 0, AA -> 26
+func ColumnIndex(name string) (index int, err error) {
+	if name == "" {
+		err = errors.New("empty column name")
+		return
+	}
+	for _, c := range strings.ToUpper(name) {
+		if c < 'A' || c > 'Z' {
+			err = errors.Errorf("invalid column name %q", name)
+			return
+		}
+		index = index*26 + int(c-'A') + 1
+	}
+	index--
+	return
+}
+
+// CellRef 返回从0开始的列、行下标对应的单元格,如 (0, 0) -> A1
+func CellRef(col, row int) string {
+	return ColumnName(col) + strconv.Itoa(row+1)
+}
+
+// ParseCellRef 解析单元格,返回从0开始的列、行下标,如 B3 -> (1, 2)
+func ParseCellRef(ref string) (col, row int, err error) {
+	i := strings.IndexFunc(ref, func(r rune) bool { return r >= '0' && r <= '9' })
+	if i <= 0 {
+		err = errors.Errorf("invalid cell %q", ref)
+		return
+	}
+	if col, err = ColumnIndex(ref[:i]); err != nil {
+		return
+	}
+	if row, err = strconv.Atoi(ref[i:]); err != nil || row <= 0 {
+		err = errors.Errorf("invalid cell %q", ref)
+		return
+	}
+	row--
+	return
+}
+
+// SheetRange 拼接 sheetId!A1:B2 形式的范围,startCol/startRow 与 endCol/endRow 均为从0开始的下标且包含结束位置
+func SheetRange(sheetId string, startCol, startRow, endCol, endRow int) string {
+	return sheetId + "!" + CellRef(startCol, startRow) + ":" + CellRef(endCol, endRow)
+}
+
+// parseRangeBounds 解析 A1:C3 形式的范围,返回从0开始的起止列、行下标;单个单元格视为起止相同
+func parseRangeBounds(r string) (startCol, startRow, endCol, endRow int, err error) {
+	if i := strings.LastIndex(r, "!"); i >= 0 {
+		r = r[i+1:]
+	}
+	start, end := r, r
+	if i := strings.Index(r, ":"); i >= 0 {
+		start, end = r[:i], r[i+1:]
+	}
+	if startCol, startRow, err = ParseCellRef(start); err != nil {
+		return
+	}
+	endCol, endRow, err = ParseCellRef(end)
+	return
+}
@@ -0,0 +1,108 @@
+package lark_util
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+/** -------------------------------------------------乐观并发-------------------------------------------------------------- **/
+
+// RevisionConflictError 表格在读取之后被其他写入方修改过
+type RevisionConflictError struct {
+	ExcelToken string
+	Expected   int // 读取时的版本号
+	Actual     int // 写入前检查到的版本号
+}
+
+func (e *RevisionConflictError) Error() string {
+	return fmt.Sprintf("spreadsheet %s revision conflict: expected %d, actual %d", e.ExcelToken, e.Expected, e.Actual)
+}
+
+// RevisionTxn 一次乐观并发的修改过程,Info为开始时读取到的元数据,Revision为之后写入检查的基准版本号
+type RevisionTxn struct {
+	l          *LarkU
+	ExcelToken string
+	Info       *ExcelInfo
+	Revision   int
+}
+
+func (l *LarkU) readRevision(excelToken string) (info *ExcelInfo, revision int, err error) {
+	l.InvalidateExcelInfo(excelToken)
+	if info, err = l.GetExcelInfo(excelToken, "", ""); err != nil {
+		return
+	}
+	if info.Properties != nil {
+		revision = info.Properties.Revision
+	}
+	return
+}
+
+// Check 重新读取版本号,与Revision不同时返回*RevisionConflictError
+func (t *RevisionTxn) Check() (err error) {
+	_, revision, err := t.l.readRevision(t.ExcelToken)
+	if err != nil {
+		return
+	}
+	if revision != t.Revision {
+		err = &RevisionConflictError{ExcelToken: t.ExcelToken, Expected: t.Revision, Actual: revision}
+	}
+	return
+}
+
+// Mutate 检查版本号未变化后执行写入,write需要返回写入接口响应中的版本号,作为之后检查的基准;
+// 写入之后其他写入方的修改因此不会被当作自己的,会在下一次Mutate或Check时报告冲突.
+// 飞书没有带版本号条件的写入,检查与写入之间仍有很短的窗口,只能降低而不能完全避免覆盖
+func (t *RevisionTxn) Mutate(write func() (revision int, err error)) (err error) {
+	if err = t.Check(); err != nil {
+		return
+	}
+	revision, err := write()
+	if err != nil {
+		return
+	}
+	if revision <= 0 {
+		return errors.Errorf("remote service error: no revision returned for spreadsheet %s", t.ExcelToken)
+	}
+	t.Revision = revision
+	return
+}
+
+// WriteValues 通过Mutate覆盖写入数据,见WriteValueToCell
+func (t *RevisionTxn) WriteValues(valueRange InsertValueToCellValueRange) (err error) {
+	return t.Mutate(func() (int, error) {
+		return t.l.writeValueToCell(&WriteValueToCellReq{ExcelToken: t.ExcelToken, ValueRange: valueRange})
+	})
+}
+
+// BatchWriteValues 通过Mutate一次覆盖写入多个范围,见BatchWriteValueToCell
+func (t *RevisionTxn) BatchWriteValues(valueRanges []InsertValueToCellValueRange) (err error) {
+	return t.Mutate(func() (int, error) {
+		return t.l.batchWriteValueToCell(&BatchWriteValueToCellReq{ExcelToken: t.ExcelToken, ValueRanges: valueRanges})
+	})
+}
+
+// WithRevision 读取表格版本号后执行fn,fn中的写入需要通过txn.Mutate或txn.WriteValues等进行.
+// 检测到冲突时调用onConflict(attempt从1开始),返回true且未超过maxAttempts时重新读取并重试fn,onConflict为nil时总是重试;
+// 最终仍冲突时返回*RevisionConflictError,可以通过errors.As判断
+func (l *LarkU) WithRevision(excelToken string, maxAttempts int, fn func(txn *RevisionTxn) error, onConflict func(attempt int, err *RevisionConflictError) bool) (err error) {
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+	for attempt := 1; ; attempt++ {
+		txn := &RevisionTxn{l: l, ExcelToken: excelToken}
+		if txn.Info, txn.Revision, err = l.readRevision(excelToken); err != nil {
+			return
+		}
+		err = fn(txn)
+		var conflict *RevisionConflictError
+		if !errors.As(err, &conflict) || attempt >= maxAttempts {
+			return
+		}
+		if onConflict != nil && !onConflict(attempt, conflict) {
+			return
+		}
+	}
+}
+
+/** -------------------------------------------------乐观并发-------------------------------------------------------------- **/
@@ -0,0 +1,114 @@
+package lark_util
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+/** -------------------------------------------------sheet查找-------------------------------------------------------------- **/
+
+// ErrSheetNotFound 按标题、下标或sheetId找不到sheet
+var ErrSheetNotFound = errors.New("sheet not found")
+
+// CachedExcelInfo 获取表格的元数据,短时间内(30秒)重复调用时使用缓存.
+// 通过本库修改sheet或行列、以及读取数据时发现版本号变化,都会使缓存失效
+func (l *LarkU) CachedExcelInfo(excelToken string) (info *ExcelInfo, err error) {
+	if info, ok := l.infoCache.Get(excelToken); ok {
+		return info, nil
+	}
+	if info, err = l.GetExcelInfo(excelToken, "", ""); err != nil {
+		return
+	}
+	l.infoCache.Set(excelToken, info)
+	return
+}
+
+// InvalidateExcelInfo 使表格元数据的缓存失效
+func (l *LarkU) InvalidateExcelInfo(excelToken string) {
+	l.infoCache.Delete(excelToken)
+}
+
+// observeRevision 发现表格的版本号比缓存的新时,说明表格被修改过,使缓存失效
+func (l *LarkU) observeRevision(excelToken string, revision int) {
+	l.infoCache.DeleteIf(excelToken, func(info *ExcelInfo) bool {
+		return info.Properties != nil && info.Properties.Revision < revision
+	})
+}
+
+// SheetByTitle 按标题查找sheet
+func (l *LarkU) SheetByTitle(excelToken, title string) (sheet *Sheets, err error) {
+	info, err := l.CachedExcelInfo(excelToken)
+	if err != nil {
+		return
+	}
+	for _, s := range info.Sheets {
+		if s.Title == title {
+			return s, nil
+		}
+	}
+	err = errors.Wrapf(ErrSheetNotFound, "title %q in %s", title, excelToken)
+	return
+}
+
+// SheetByIndex 按位置查找sheet,index从0开始
+func (l *LarkU) SheetByIndex(excelToken string, index int) (sheet *Sheets, err error) {
+	info, err := l.CachedExcelInfo(excelToken)
+	if err != nil {
+		return
+	}
+	for _, s := range info.Sheets {
+		if s.Index == index {
+			return s, nil
+		}
+	}
+	err = errors.Wrapf(ErrSheetNotFound, "index %d in %s", index, excelToken)
+	return
+}
+
+// EnsureSheet 按标题查找sheet,不存在时在末尾新增
+func (l *LarkU) EnsureSheet(excelToken, title string) (sheet *Sheets, err error) {
+	sheet, err = l.SheetByTitle(excelToken, title)
+	if !errors.Is(err, ErrSheetNotFound) {
+		return
+	}
+	info, err := l.GetExcelInfo(excelToken, "", "")
+	if err != nil {
+		return
+	}
+	if err = l.ExecSheetBatch(NewSheetBatch(excelToken).AddSheet(title, len(info.Sheets))); err != nil {
+		return
+	}
+	return l.SheetByTitle(excelToken, title)
+}
+
+// ResolveRange 把用sheet标题表示的范围转换为用sheetId表示,如 2026-Q3!A1:B2 -> 0b12ef!A1:B2;
+// 标题可以用单引号括起来,前缀已经是sheetId或者没有前缀时原样返回
+func (l *LarkU) ResolveRange(excelToken, cellRange string) (resolved string, err error) {
+	i := strings.LastIndex(cellRange, "!")
+	if i < 0 {
+		return cellRange, nil
+	}
+	prefix := cellRange[:i]
+	info, err := l.CachedExcelInfo(excelToken)
+	if err != nil {
+		return
+	}
+	for _, s := range info.Sheets {
+		if s.SheetId == prefix {
+			return cellRange, nil
+		}
+	}
+	title := prefix
+	if len(title) >= 2 && strings.HasPrefix(title, "'") && strings.HasSuffix(title, "'") {
+		title = strings.ReplaceAll(title[1:len(title)-1], "''", "'")
+	}
+	sheet, err := l.SheetByTitle(excelToken, title)
+	if err != nil {
+		return
+	}
+	resolved = sheet.SheetId + cellRange[i:]
+	return
+}
+
+/** -------------------------------------------------sheet查找-------------------------------------------------------------- **/
@@ -0,0 +1,228 @@
+package lark_util
+
+import (
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+/** -------------------------------------------------sheet批量操作-------------------------------------------------------------- **/
+
+const maxSheetTitleLength = 100 // sheet标题的最大长度
+
+type sheetOp struct {
+	kind    string // add、copy、delete、rename、move、hide、show、freeze、lock、unlock
+	sheetId string
+	title   string
+	index   int
+	rows    int
+	cols    int
+	users   int // lock时额外可编辑的用户数
+}
+
+// SheetBatch 构造一次 sheets_batch_update 的操作,通过 ExecSheetBatch 校验后发送.
+// 每个方法都返回自身以便链式调用,参数错误会在校验时统一返回
+type SheetBatch struct {
+	excelToken string
+	userIdType string
+	ops        []sheetOp
+	requests   []*HandleSheetRequest
+}
+
+func NewSheetBatch(excelToken string) *SheetBatch {
+	return &SheetBatch{excelToken: excelToken}
+}
+
+// UserIdType 设置Lock中userIds的类型,可选open_id、union_id,传入userIds时必须设置
+func (b *SheetBatch) UserIdType(userIdType string) *SheetBatch {
+	b.userIdType = userIdType
+	return b
+}
+
+func (b *SheetBatch) add(op sheetOp, req *HandleSheetRequest) *SheetBatch {
+	b.ops = append(b.ops, op)
+	b.requests = append(b.requests, req)
+	return b
+}
+
+// AddSheet 在index位置新增sheet,index从0开始
+func (b *SheetBatch) AddSheet(title string, index int) *SheetBatch {
+	return b.add(sheetOp{kind: "add", title: title, index: index}, &HandleSheetRequest{
+		AddSheet: &AddSheet{Properties: HandleSheetProperties{Title: title, Index: strconv.Itoa(index)}},
+	})
+}
+
+// Copy 复制sheet,newTitle为空时由飞书命名为 原标题(副本_0)
+func (b *SheetBatch) Copy(sheetId, newTitle string) *SheetBatch {
+	req := &HandleSheetRequest{CopySheet: &CopySheet{Source: &HandleSheetSource{SheetID: sheetId}}}
+	if newTitle != "" {
+		req.CopySheet.Destination = &HandleSheetDestination{Title: newTitle}
+	}
+	return b.add(sheetOp{kind: "copy", sheetId: sheetId, title: newTitle}, req)
+}
+
+// Delete 删除sheet
+func (b *SheetBatch) Delete(sheetId string) *SheetBatch {
+	return b.add(sheetOp{kind: "delete", sheetId: sheetId}, &HandleSheetRequest{
+		DeleteSheet: &DeleteSheet{SheetID: sheetId},
+	})
+}
+
+func (b *SheetBatch) update(op sheetOp, properties *HandleSheetProperties) *SheetBatch {
+	properties.SheetID = op.sheetId
+	return b.add(op, &HandleSheetRequest{UpdateSheet: &UpdateSheet{Properties: properties}})
+}
+
+// Rename 修改sheet标题
+func (b *SheetBatch) Rename(sheetId, title string) *SheetBatch {
+	return b.update(sheetOp{kind: "rename", sheetId: sheetId, title: title}, &HandleSheetProperties{Title: title})
+}
+
+// Move 把sheet移动到index位置,index从0开始
+func (b *SheetBatch) Move(sheetId string, index int) *SheetBatch {
+	return b.update(sheetOp{kind: "move", sheetId: sheetId, index: index}, &HandleSheetProperties{Index: strconv.Itoa(index)})
+}
+
+// Hide 隐藏sheet
+func (b *SheetBatch) Hide(sheetId string) *SheetBatch {
+	return b.update(sheetOp{kind: "hide", sheetId: sheetId}, &HandleSheetProperties{Hidden: "true"})
+}
+
+// Show 取消隐藏sheet
+func (b *SheetBatch) Show(sheetId string) *SheetBatch {
+	return b.update(sheetOp{kind: "show", sheetId: sheetId}, &HandleSheetProperties{Hidden: "false"})
+}
+
+// Freeze 冻结前rows行和前cols列,0表示取消冻结
+func (b *SheetBatch) Freeze(sheetId string, rows, cols int) *SheetBatch {
+	return b.update(sheetOp{kind: "freeze", sheetId: sheetId, rows: rows, cols: cols}, &HandleSheetProperties{
+		FrozenRowCount: strconv.Itoa(rows),
+		FrozenColCount: strconv.Itoa(cols),
+	})
+}
+
+// Lock 锁定sheet,除了本人与所有者外,userIds中的用户也可以编辑,userIds的类型由UserIdType设置
+func (b *SheetBatch) Lock(sheetId string, userIds ...string) *SheetBatch {
+	return b.update(sheetOp{kind: "lock", sheetId: sheetId, users: len(userIds)}, &HandleSheetProperties{
+		Protect: &HandleSheetProtect{Lock: "LOCK", UserIDs: userIds},
+	})
+}
+
+// Unlock 解锁sheet
+func (b *SheetBatch) Unlock(sheetId string) *SheetBatch {
+	return b.update(sheetOp{kind: "unlock", sheetId: sheetId}, &HandleSheetProperties{
+		Protect: &HandleSheetProtect{Lock: "UNLOCK"},
+	})
+}
+
+// Len 返回操作数
+func (b *SheetBatch) Len() int {
+	return len(b.ops)
+}
+
+// Request 返回可以直接传给 HandleSheet 的请求
+func (b *SheetBatch) Request() *HandleSheetReq {
+	return &HandleSheetReq{ExcelToken: b.excelToken, UserIdType: b.userIdType, Requests: b.requests}
+}
+
+// Validate 基于表格元数据按顺序模拟每个操作并校验:sheet存在、标题非空不超过100个字符且不重复、
+// 位置不越界、冻结的行列数不超过sheet的行列数、锁定时指定了可编辑用户则设置了UserIdType
+func (b *SheetBatch) Validate(info *ExcelInfo) error {
+	type simSheet struct {
+		title      string
+		rows, cols int
+	}
+	sheets := make(map[string]*simSheet, len(info.Sheets))
+	titles := make(map[string]bool, len(info.Sheets))
+	for _, s := range info.Sheets {
+		sheets[s.SheetId] = &simSheet{title: s.Title, rows: s.RowCount, cols: s.ColumnCount}
+		titles[s.Title] = true
+	}
+	count := len(info.Sheets)
+	checkTitle := func(title string) error {
+		if title == "" {
+			return errors.New("empty sheet title")
+		}
+		if utf8.RuneCountInString(title) > maxSheetTitleLength {
+			return errors.Errorf("sheet title %q exceeds %d characters", title, maxSheetTitleLength)
+		}
+		if titles[title] {
+			return errors.Errorf("duplicate sheet title %q", title)
+		}
+		return nil
+	}
+	for i, op := range b.ops {
+		var sheet *simSheet
+		if op.kind != "add" {
+			if sheet = sheets[op.sheetId]; sheet == nil {
+				return errors.Errorf("op %d %s: sheet %s not found", i, op.kind, op.sheetId)
+			}
+		}
+		var err error
+		switch op.kind {
+		case "add":
+			if err = checkTitle(op.title); err == nil && (op.index < 0 || op.index > count) {
+				err = errors.Errorf("index %d out of range [0, %d]", op.index, count)
+			}
+			titles[op.title] = true
+			count++
+		case "copy":
+			if op.title != "" {
+				err = checkTitle(op.title)
+				titles[op.title] = true
+			}
+			count++
+		case "delete":
+			if count <= 1 {
+				err = errors.New("can not delete the last sheet")
+			}
+			delete(titles, sheet.title)
+			delete(sheets, op.sheetId)
+			count--
+		case "rename":
+			if op.title != sheet.title {
+				if err = checkTitle(op.title); err == nil {
+					delete(titles, sheet.title)
+					titles[op.title] = true
+					sheet.title = op.title
+				}
+			}
+		case "move":
+			if op.index < 0 || op.index >= count {
+				err = errors.Errorf("index %d out of range [0, %d)", op.index, count)
+			}
+		case "freeze":
+			if op.rows < 0 || op.rows > sheet.rows {
+				err = errors.Errorf("frozen rows %d out of range [0, %d]", op.rows, sheet.rows)
+			} else if op.cols < 0 || op.cols > sheet.cols {
+				err = errors.Errorf("frozen cols %d out of range [0, %d]", op.cols, sheet.cols)
+			}
+		case "lock":
+			if op.users > 0 && b.userIdType == "" {
+				err = errors.New("user id type is required when lock with user ids")
+			}
+		}
+		if err != nil {
+			return errors.Wrapf(err, "op %d %s", i, op.kind)
+		}
+	}
+	return nil
+}
+
+// ExecSheetBatch 读取表格元数据校验后一次性发送所有操作
+func (l *LarkU) ExecSheetBatch(b *SheetBatch) (err error) {
+	if b.Len() == 0 {
+		return
+	}
+	info, err := l.GetExcelInfo(b.excelToken, "", "")
+	if err != nil {
+		return
+	}
+	if err = b.Validate(info); err != nil {
+		return
+	}
+	return l.HandleSheet(b.Request())
+}
+
+/** -------------------------------------------------sheet批量操作-------------------------------------------------------------- **/
@@ -0,0 +1,252 @@
+package lark_util
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+/** -------------------------------------------------声明式表格---------------------------------------------------------------- **/
+
+type (
+	// ExcelSpec 声明式描述的表格,通过RenderExcel创建
+	ExcelSpec struct {
+		Title       string       `json:"title"`
+		FolderToken string       `json:"folderToken"` // 同CreateExcel的folderToken
+		Sheets      []*SheetSpec `json:"sheets"`
+	}
+	SheetSpec struct {
+		Title          string          `json:"title"`
+		Header         [][]interface{} `json:"header,omitempty"`       // 表头行,从A1开始写入
+		HeaderStyle    *Style          `json:"headerStyle,omitempty"`  // 表头行的样式
+		Rows           [][]interface{} `json:"rows,omitempty"`         // 数据行,紧接在表头之后写入
+		ColumnWidths   []int           `json:"columnWidths,omitempty"` // 按列下标设置列宽,0为不设置
+		Merges         []MergeSpec     `json:"merges,omitempty"`
+		Styles         []StyleSpec     `json:"styles,omitempty"`
+		FrozenRowCount int             `json:"frozenRowCount,omitempty"`
+		FrozenColCount int             `json:"frozenColCount,omitempty"`
+	}
+	MergeSpec struct {
+		Range     string `json:"range"`               // 不带sheetId的范围,如 A1:C1
+		MergeType string `json:"mergeType,omitempty"` // 见MergeCellType开头的常量,默认MERGE_ALL
+	}
+	StyleSpec struct {
+		Ranges []string `json:"ranges"` // 不带sheetId的范围,如 A2:A100
+		Style  Style    `json:"style"`
+	}
+)
+
+// LoadExcelSpec 从json中读取表格描述
+func LoadExcelSpec(r io.Reader) (spec *ExcelSpec, err error) {
+	spec = new(ExcelSpec)
+	if err = json.NewDecoder(r).Decode(spec); err != nil {
+		err = errors.Wrap(err, "decode excel spec")
+		return
+	}
+	err = spec.Validate()
+	return
+}
+
+// Validate 校验表格描述:至少一个sheet,sheet标题非空且不重复,范围可以解析
+func (spec *ExcelSpec) Validate() error {
+	if len(spec.Sheets) == 0 {
+		return errors.New("excel spec has no sheet")
+	}
+	titles := make(map[string]bool, len(spec.Sheets))
+	for i, sheet := range spec.Sheets {
+		if sheet.Title == "" {
+			return errors.Errorf("sheet %d has empty title", i)
+		}
+		if titles[sheet.Title] {
+			return errors.Errorf("duplicate sheet title %q", sheet.Title)
+		}
+		titles[sheet.Title] = true
+		for _, merge := range sheet.Merges {
+			if _, _, _, _, err := parseRangeBounds(merge.Range); err != nil {
+				return errors.Wrapf(err, "merge of sheet %q", sheet.Title)
+			}
+		}
+		for _, style := range sheet.Styles {
+			for _, r := range style.Ranges {
+				if _, _, _, _, err := parseRangeBounds(r); err != nil {
+					return errors.Wrapf(err, "style of sheet %q", sheet.Title)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// size 返回写入数据后需要的行数和列数
+func (sheet *SheetSpec) size() (rows, cols int) {
+	rows = len(sheet.Header) + len(sheet.Rows)
+	for _, rs := range [][][]interface{}{sheet.Header, sheet.Rows} {
+		for _, row := range rs {
+			if len(row) > cols {
+				cols = len(row)
+			}
+		}
+	}
+	if len(sheet.ColumnWidths) > cols {
+		cols = len(sheet.ColumnWidths)
+	}
+	return
+}
+
+// RenderExcel 按描述创建表格并返回spreadsheetToken.调用顺序为:创建表格、一次批量重命名/新增sheet、
+// 补足行列、批量写入数据、合并单元格、一次批量设置样式、一次批量冻结、按连续相同的列宽设置列宽
+func (l *LarkU) RenderExcel(spec *ExcelSpec) (spreadsheetToken string, err error) {
+	if err = spec.Validate(); err != nil {
+		return
+	}
+	spreadsheetToken, err = l.CreateExcel(spec.FolderToken, spec.Title)
+	if err != nil {
+		return
+	}
+	info, err := l.GetExcelInfo(spreadsheetToken, "", "")
+	if err != nil {
+		return
+	}
+	if len(info.Sheets) == 0 {
+		err = errors.Errorf("spreadsheet %s has no default sheet", spreadsheetToken)
+		return
+	}
+
+	// 默认sheet重命名为第一个sheet,其余的依次新增
+	batch := NewSheetBatch(spreadsheetToken).Rename(info.Sheets[0].SheetId, spec.Sheets[0].Title)
+	for i, sheet := range spec.Sheets[1:] {
+		batch.AddSheet(sheet.Title, i+1)
+	}
+	if err = batch.Validate(info); err == nil {
+		err = l.HandleSheet(batch.Request())
+	}
+	if err != nil {
+		err = errors.Wrap(err, "create sheets")
+		return
+	}
+	if info, err = l.GetExcelInfo(spreadsheetToken, "", ""); err != nil {
+		return
+	}
+	sheets := make(map[string]*Sheets, len(info.Sheets))
+	for _, s := range info.Sheets {
+		sheets[s.Title] = s
+	}
+
+	var (
+		valueRanges []InsertValueToCellValueRange
+		styles      []Data
+		frozen      = NewSheetBatch(spreadsheetToken)
+	)
+	for _, sheetSpec := range spec.Sheets {
+		sheet, ok := sheets[sheetSpec.Title]
+		if !ok {
+			err = errors.Errorf("sheet %q not found after creating", sheetSpec.Title)
+			return
+		}
+		rows, cols := sheetSpec.size()
+		if need := rows - sheet.RowCount; need > 0 {
+			if err = l.growDimension(spreadsheetToken, sheet.SheetId, MajorDimensionRows, need); err != nil {
+				return
+			}
+		}
+		if need := cols - sheet.ColumnCount; need > 0 {
+			if err = l.growDimension(spreadsheetToken, sheet.SheetId, MajorDimensionCols, need); err != nil {
+				return
+			}
+		}
+		valueRanges = append(valueRanges, splitValueRanges(sheet.SheetId, 0, append(append([][]interface{}{}, sheetSpec.Header...), sheetSpec.Rows...), cols)...)
+		if sheetSpec.HeaderStyle != nil && len(sheetSpec.Header) > 0 && cols > 0 {
+			styles = append(styles, Data{
+				Ranges: []string{SheetRange(sheet.SheetId, 0, 0, cols-1, len(sheetSpec.Header)-1)},
+				Style:  *sheetSpec.HeaderStyle,
+			})
+		}
+		for _, style := range sheetSpec.Styles {
+			ranges := make([]string, len(style.Ranges))
+			for i, r := range style.Ranges {
+				ranges[i] = sheet.SheetId + "!" + r
+			}
+			styles = append(styles, Data{Ranges: ranges, Style: style.Style})
+		}
+		if sheetSpec.FrozenRowCount > 0 || sheetSpec.FrozenColCount > 0 {
+			frozen.Freeze(sheet.SheetId, sheetSpec.FrozenRowCount, sheetSpec.FrozenColCount)
+		}
+	}
+
+	for start := 0; start < len(valueRanges); {
+		end, total := start, 0
+		for end < len(valueRanges) && (end == start || total+len(valueRanges[end].Values) <= maxWriteRows) {
+			total += len(valueRanges[end].Values)
+			end++
+		}
+		err = l.BatchWriteValueToCell(&BatchWriteValueToCellReq{ExcelToken: spreadsheetToken, ValueRanges: valueRanges[start:end]})
+		if err != nil {
+			err = errors.Wrap(err, "write values")
+			return
+		}
+		start = end
+	}
+	for _, sheetSpec := range spec.Sheets {
+		for _, merge := range sheetSpec.Merges {
+			if err = l.MergeCells(spreadsheetToken, sheets[sheetSpec.Title].SheetId, merge.Range, merge.MergeType); err != nil {
+				err = errors.Wrapf(err, "merge %s of sheet %q", merge.Range, sheetSpec.Title)
+				return
+			}
+		}
+	}
+	if len(styles) > 0 {
+		if err = l.BatchUpdateCellStyle(&BatchUpdateCellStyleReq{ExcelToken: spreadsheetToken, Data: mergeStyleData(styles)}); err != nil {
+			err = errors.Wrap(err, "set styles")
+			return
+		}
+	}
+	if frozen.Len() > 0 {
+		if err = l.ExecSheetBatch(frozen); err != nil {
+			err = errors.Wrap(err, "freeze sheets")
+			return
+		}
+	}
+	for _, sheetSpec := range spec.Sheets {
+		if err = l.setDimensionSizes(spreadsheetToken, sheets[sheetSpec.Title].SheetId, MajorDimensionCols, sheetSpec.ColumnWidths); err != nil {
+			err = errors.Wrapf(err, "set column widths of sheet %q", sheetSpec.Title)
+			return
+		}
+	}
+	return
+}
+
+// splitValueRanges 把从startRow行开始写入的数据切分为不超过5000行、100列的写入范围
+func splitValueRanges(sheetId string, startRow int, rows [][]interface{}, cols int) (valueRanges []InsertValueToCellValueRange) {
+	for r := 0; r < len(rows); r += maxWriteRows {
+		rEnd := r + maxWriteRows
+		if rEnd > len(rows) {
+			rEnd = len(rows)
+		}
+		for c := 0; c < cols; c += maxWriteCols {
+			cEnd := c + maxWriteCols
+			if cEnd > cols {
+				cEnd = cols
+			}
+			values := make([][]interface{}, rEnd-r)
+			for i := range values {
+				values[i] = make([]interface{}, cEnd-c)
+				row := rows[r+i]
+				for j := range values[i] {
+					if c+j < len(row) {
+						values[i][j] = row[c+j]
+					} else {
+						values[i][j] = ""
+					}
+				}
+			}
+			valueRanges = append(valueRanges, InsertValueToCellValueRange{
+				Range:  SheetRange(sheetId, c, startRow+r, cEnd-1, startRow+rEnd-1),
+				Values: values,
+			})
+		}
+	}
+	return
+}
+
+/** -------------------------------------------------声明式表格---------------------------------------------------------------- **/
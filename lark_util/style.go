@@ -0,0 +1,309 @@
+package lark_util
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+/** -------------------------------------------------样式-------------------------------------------------------------------- **/
+
+// 以下枚举和OptBool的零值都表示"不设置",序列化时会被忽略,因此左对齐、不加粗这类取值为0或false的样式也可以发送
+
+type (
+	// OptBool 三态布尔值,零值为不设置
+	OptBool int8
+	// HAlign 水平对齐
+	HAlign int8
+	// VAlign 垂直对齐
+	VAlign int8
+	// TextDecoration 文本装饰
+	TextDecoration int8
+	// BorderType 边框类型
+	BorderType string
+	// Color 颜色,统一为 #RRGGBB 形式,通过 HexColor 或 RGB 构造
+	Color string
+	// FontSize 字体大小,形如 10pt/1.5,通过 FontSizePt 构造
+	FontSize string
+)
+
+const (
+	BoolTrue OptBool = iota + 1
+	BoolFalse
+)
+
+const (
+	HAlignLeft HAlign = iota + 1
+	HAlignCenter
+	HAlignRight
+)
+
+const (
+	VAlignTop VAlign = iota + 1
+	VAlignMiddle
+	VAlignBottom
+)
+
+const (
+	TextDecorationNone TextDecoration = iota + 1
+	TextDecorationUnderline
+	TextDecorationLineThrough
+	TextDecorationUnderlineLineThrough
+)
+
+const (
+	BorderFull   BorderType = "FULL_BORDER"
+	BorderOuter  BorderType = "OUTER_BORDER"
+	BorderInner  BorderType = "INNER_BORDER"
+	BorderNone   BorderType = "NO_BORDER"
+	BorderLeft   BorderType = "LEFT_BORDER"
+	BorderRight  BorderType = "RIGHT_BORDER"
+	BorderTop    BorderType = "TOP_BORDER"
+	BorderBottom BorderType = "BOTTOM_BORDER"
+)
+
+// OptBoolOf 把bool转换为OptBool
+func OptBoolOf(b bool) OptBool {
+	if b {
+		return BoolTrue
+	}
+	return BoolFalse
+}
+
+func (b OptBool) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b == BoolTrue)
+}
+
+func (b *OptBool) UnmarshalJSON(data []byte) error {
+	var v bool
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*b = OptBoolOf(v)
+	return nil
+}
+
+// 枚举在飞书中从0开始,在本库中从1开始以便用零值表示不设置
+func marshalEnum(v int8) ([]byte, error) {
+	return []byte(strconv.Itoa(int(v) - 1)), nil
+}
+
+func unmarshalEnum(data []byte) (int8, error) {
+	var v int8
+	if err := json.Unmarshal(data, &v); err != nil {
+		return 0, err
+	}
+	return v + 1, nil
+}
+
+func (a HAlign) MarshalJSON() ([]byte, error) { return marshalEnum(int8(a)) }
+
+func (a *HAlign) UnmarshalJSON(data []byte) error {
+	v, err := unmarshalEnum(data)
+	*a = HAlign(v)
+	return err
+}
+
+func (a VAlign) MarshalJSON() ([]byte, error) { return marshalEnum(int8(a)) }
+
+func (a *VAlign) UnmarshalJSON(data []byte) error {
+	v, err := unmarshalEnum(data)
+	*a = VAlign(v)
+	return err
+}
+
+func (d TextDecoration) MarshalJSON() ([]byte, error) { return marshalEnum(int8(d)) }
+
+func (d *TextDecoration) UnmarshalJSON(data []byte) error {
+	v, err := unmarshalEnum(data)
+	*d = TextDecoration(v)
+	return err
+}
+
+// HexColor 解析 #RGB、#RRGGBB 形式的颜色,#可以省略
+func HexColor(s string) (c Color, err error) {
+	h := strings.TrimPrefix(s, "#")
+	if len(h) == 3 {
+		h = string([]byte{h[0], h[0], h[1], h[1], h[2], h[2]})
+	}
+	if len(h) != 6 {
+		err = errors.Errorf("invalid hex color %q", s)
+		return
+	}
+	if _, e := strconv.ParseUint(h, 16, 32); e != nil {
+		err = errors.Errorf("invalid hex color %q", s)
+		return
+	}
+	c = Color("#" + strings.ToUpper(h))
+	return
+}
+
+// MustHexColor 同HexColor,解析失败时panic,用于常量颜色
+func MustHexColor(s string) Color {
+	c, err := HexColor(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// RGB 由红绿蓝分量构造颜色
+func RGB(r, g, b uint8) Color {
+	return Color(fmt.Sprintf("#%02X%02X%02X", r, g, b))
+}
+
+// FontSizePt 以磅为单位的字体大小,行高为1.5倍
+func FontSizePt(pt int) FontSize {
+	return FontSize(strconv.Itoa(pt) + "pt/1.5")
+}
+
+type (
+	CellFont struct {
+		Bold     OptBool  `json:"bold,omitempty"`     // 是否加粗
+		Italic   OptBool  `json:"italic,omitempty"`   // 是否斜体
+		FontSize FontSize `json:"fontSize,omitempty"` // 字体大小
+		Clean    OptBool  `json:"clean,omitempty"`    // 清除 font 格式
+	}
+	// CellStyle 强类型的单元格样式,零值字段不会发送,可以通过Merge叠加
+	CellStyle struct {
+		Font           *CellFont      `json:"font,omitempty"`           // 字体
+		TextDecoration TextDecoration `json:"textDecoration,omitempty"` // 文本装饰
+		Formatter      string         `json:"formatter,omitempty"`      // 数字格式
+		HAlign         HAlign         `json:"hAlign,omitempty"`         // 水平对齐
+		VAlign         VAlign         `json:"vAlign,omitempty"`         // 垂直对齐
+		ForeColor      Color          `json:"foreColor,omitempty"`      // 字体颜色
+		BackColor      Color          `json:"backColor,omitempty"`      // 背景颜色
+		BorderType     BorderType     `json:"borderType,omitempty"`     // 边框类型
+		BorderColor    Color          `json:"borderColor,omitempty"`    // 边框颜色
+		Clean          OptBool        `json:"clean,omitempty"`          // 是否清除所有格式
+	}
+)
+
+// Merge 以s为基础叠加override中设置了的字段,返回新的样式,s和override都不会被修改
+func (s CellStyle) Merge(override CellStyle) CellStyle {
+	if override.Font != nil {
+		font := CellFont{}
+		if s.Font != nil {
+			font = *s.Font
+		}
+		if override.Font.Bold != 0 {
+			font.Bold = override.Font.Bold
+		}
+		if override.Font.Italic != 0 {
+			font.Italic = override.Font.Italic
+		}
+		if override.Font.FontSize != "" {
+			font.FontSize = override.Font.FontSize
+		}
+		if override.Font.Clean != 0 {
+			font.Clean = override.Font.Clean
+		}
+		s.Font = &font
+	}
+	if override.TextDecoration != 0 {
+		s.TextDecoration = override.TextDecoration
+	}
+	if override.Formatter != "" {
+		s.Formatter = override.Formatter
+	}
+	if override.HAlign != 0 {
+		s.HAlign = override.HAlign
+	}
+	if override.VAlign != 0 {
+		s.VAlign = override.VAlign
+	}
+	if override.ForeColor != "" {
+		s.ForeColor = override.ForeColor
+	}
+	if override.BackColor != "" {
+		s.BackColor = override.BackColor
+	}
+	if override.BorderType != "" {
+		s.BorderType = override.BorderType
+	}
+	if override.BorderColor != "" {
+		s.BorderColor = override.BorderColor
+	}
+	if override.Clean != 0 {
+		s.Clean = override.Clean
+	}
+	return s
+}
+
+// CellStyleOf 把Style转换为CellStyle;Style中为0或false的字段视为不设置
+func CellStyleOf(style Style) CellStyle {
+	s := CellStyle{
+		Formatter:   style.Formatter,
+		ForeColor:   Color(style.ForeColor),
+		BackColor:   Color(style.BackColor),
+		BorderType:  BorderType(style.BorderType),
+		BorderColor: Color(style.BorderColor),
+	}
+	if style.Font != (Font{}) {
+		s.Font = &CellFont{FontSize: FontSize(style.Font.FontSize)}
+		if style.Font.Bold {
+			s.Font.Bold = BoolTrue
+		}
+		if style.Font.Italic {
+			s.Font.Italic = BoolTrue
+		}
+		if style.Font.Clean {
+			s.Font.Clean = BoolTrue
+		}
+	}
+	if style.TextDecoration != 0 {
+		s.TextDecoration = TextDecoration(style.TextDecoration + 1)
+	}
+	if style.HAlign != 0 {
+		s.HAlign = HAlign(style.HAlign + 1)
+	}
+	if style.VAlign != 0 {
+		s.VAlign = VAlign(style.VAlign + 1)
+	}
+	if style.Clean {
+		s.Clean = BoolTrue
+	}
+	return s
+}
+
+type (
+	BatchUpdateCellStylesReq struct {
+		ExcelToken string
+		Data       []CellStyleData `json:"data"`
+	}
+	CellStyleData struct {
+		Ranges []string  `json:"ranges"`
+		Style  CellStyle `json:"style"`
+	}
+)
+
+// BatchUpdateCellStyles 批量设置单元格样式,同BatchUpdateCellStyle,但使用强类型的CellStyle
+func (l *LarkU) BatchUpdateCellStyles(req *BatchUpdateCellStylesReq) (err error) {
+	httpCode, respBody, err := l.LarkPut("/open-apis/sheets/v2/spreadsheets/"+req.ExcelToken+"/styles_batch_update", map[string]interface{}{
+		"data": req.Data,
+	})
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type BatchUpdateCellStylesResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+	}
+	m := new(BatchUpdateCellStylesResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+	}
+	return
+}
+
+/** -------------------------------------------------样式-------------------------------------------------------------------- **/
@@ -0,0 +1,238 @@
+package lark_util
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"hash/adler32"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+/** -------------------------------------------------文件上传下载-------------------------------------------------------------- **/
+
+// MaxUploadAllSize 一次上传的文件大小上限,超过时需要分片上传
+const MaxUploadAllSize = 20 * 1024 * 1024
+
+type UploadFileReq struct {
+	FolderToken string         // 上传到的文件夹,同CreateExcel的folderToken
+	FileName    string         // 文件名,如 report-2026-10.pdf
+	File        io.ReaderAt    // 文件内容,分片上传时按偏移读取,断点续传时不需要从头读取
+	Size        int64          // 文件大小
+	Session     *UploadSession // 分片上传的进度,为nil时新建;上传中断后传入同一个Session即可续传
+}
+
+// UploadSession 分片上传的进度,可以序列化保存,用于断点续传
+type UploadSession struct {
+	UploadId  string `json:"upload_id"`
+	BlockSize int64  `json:"block_size"`
+	BlockNum  int    `json:"block_num"`
+	Uploaded  []bool `json:"uploaded"` // 每个分片是否已上传
+}
+
+// UploadFile 上传文件到文件夹,返回文件的token.不超过20MB的文件一次上传,超过时使用
+// upload_prepare/upload_part/upload_finish分片上传,每个分片带Adler-32校验和;中断后可以用req.Session续传
+func (l *LarkU) UploadFile(ctx context.Context, req *UploadFileReq) (fileToken string, err error) {
+	if req.Size <= 0 {
+		err = errors.Errorf("invalid upload file size %d", req.Size)
+		return
+	}
+	if req.Size <= MaxUploadAllSize && req.Session == nil {
+		content := make([]byte, req.Size)
+		n, e := req.File.ReadAt(content, 0)
+		if e != nil && e != io.EOF {
+			err = errors.Wrap(e, "read upload file")
+			return
+		}
+		if int64(n) != req.Size {
+			err = errors.Errorf("read %d bytes from upload file, expected %d", n, req.Size)
+			return
+		}
+		return l.uploadAll(ctx, req.FolderToken, req.FileName, content)
+	}
+	if req.Session == nil || req.Session.UploadId == "" {
+		if req.Session, err = l.uploadPrepare(req.FolderToken, req.FileName, req.Size); err != nil {
+			return
+		}
+	}
+	session := req.Session
+	if len(session.Uploaded) != session.BlockNum {
+		session.Uploaded = make([]bool, session.BlockNum)
+	}
+	buf := make([]byte, session.BlockSize)
+	for seq := 0; seq < session.BlockNum; seq++ {
+		if session.Uploaded[seq] {
+			continue
+		}
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		offset := int64(seq) * session.BlockSize
+		expected := req.Size - offset
+		if expected > session.BlockSize {
+			expected = session.BlockSize
+		}
+		if expected <= 0 {
+			err = errors.Errorf("part %d starts at %d, beyond file size %d", seq, offset, req.Size)
+			return
+		}
+		n, e := req.File.ReadAt(buf[:expected], offset)
+		if e != nil && e != io.EOF {
+			err = errors.Wrapf(e, "read part %d", seq)
+			return
+		}
+		if int64(n) != expected {
+			err = errors.Errorf("read %d bytes for part %d, expected %d", n, seq, expected)
+			return
+		}
+		if err = l.uploadPart(ctx, session.UploadId, seq, buf[:n]); err != nil {
+			err = errors.Wrapf(err, "upload part %d", seq)
+			return
+		}
+		session.Uploaded[seq] = true
+	}
+	return l.uploadFinish(session)
+}
+
+func (l *LarkU) uploadAll(ctx context.Context, folderToken, fileName string, content []byte) (fileToken string, err error) {
+	httpCode, respBody, err := l.LarkPostMultipart(ctx, "/open-apis/drive/v1/files/upload_all", map[string]string{
+		"file_name":   fileName,
+		"parent_type": "explorer",
+		"parent_node": folderToken,
+		"size":        strconv.Itoa(len(content)),
+		"checksum":    strconv.FormatUint(uint64(adler32.Checksum(content)), 10),
+	}, "file", fileName, bytes.NewReader(content))
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type UploadAllResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data struct {
+			FileToken string `json:"file_token,omitempty"`
+		}
+	}
+	m := new(UploadAllResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	fileToken = m.Data.FileToken
+	return
+}
+
+func (l *LarkU) uploadPrepare(folderToken, fileName string, size int64) (session *UploadSession, err error) {
+	httpCode, respBody, err := l.LarkPost("/open-apis/drive/v1/files/upload_prepare", map[string]interface{}{
+		"file_name":   fileName,
+		"parent_type": "explorer",
+		"parent_node": folderToken,
+		"size":        size,
+	})
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type UploadPrepareResp struct {
+		Code int32          `json:"code,omitempty"`
+		Msg  string         `json:"msg,omitempty"`
+		Data *UploadSession `json:"data,omitempty"`
+	}
+	m := new(UploadPrepareResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	if m.Data == nil || m.Data.UploadId == "" || m.Data.BlockSize <= 0 {
+		err = errors.Errorf("remote service error: invalid upload prepare result | %s", string(respBody))
+		return
+	}
+	session = m.Data
+	session.Uploaded = make([]bool, session.BlockNum)
+	return
+}
+
+func (l *LarkU) uploadPart(ctx context.Context, uploadId string, seq int, part []byte) (err error) {
+	httpCode, respBody, err := l.LarkPostMultipart(ctx, "/open-apis/drive/v1/files/upload_part", map[string]string{
+		"upload_id": uploadId,
+		"seq":       strconv.Itoa(seq),
+		"size":      strconv.Itoa(len(part)),
+		"checksum":  strconv.FormatUint(uint64(adler32.Checksum(part)), 10),
+	}, "file", "part", bytes.NewReader(part))
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type UploadPartResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+	}
+	m := new(UploadPartResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+	}
+	return
+}
+
+func (l *LarkU) uploadFinish(session *UploadSession) (fileToken string, err error) {
+	httpCode, respBody, err := l.LarkPost("/open-apis/drive/v1/files/upload_finish", map[string]interface{}{
+		"upload_id": session.UploadId,
+		"block_num": session.BlockNum,
+	})
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type UploadFinishResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data struct {
+			FileToken string `json:"file_token,omitempty"`
+		}
+	}
+	m := new(UploadFinishResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	fileToken = m.Data.FileToken
+	return
+}
+
+// DownloadFile 下载云空间中的文件并流式写入w
+func (l *LarkU) DownloadFile(ctx context.Context, fileToken string, w io.Writer) (err error) {
+	httpCode, respBody, err := l.LarkDownload(ctx, "/open-apis/drive/v1/files/"+fileToken+"/download", nil, w)
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+	}
+	return
+}
+
+/** -------------------------------------------------文件上传下载-------------------------------------------------------------- **/
@@ -2,44 +2,350 @@ package lark_util
 
 import (
 	"encoding/json"
-	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/pkg/errors"
 )
 
+/** -------------------------------------------------用户-------------------------------------------------------------------- **/
+
+// 用户id类型
+const (
+	UserIdTypeOpenId  = "open_id"
+	UserIdTypeUnionId = "union_id"
+	UserIdTypeUserId  = "user_id"
+)
+
+// maxBatchGetUserId batch_get_id每次最多查询50个邮箱和50个手机号
+const maxBatchGetUserId = 50
+
+// 找不到用户的结果缓存的时间较短,便于新入职的员工尽快能查到
+const userIdNotFoundTTL = time.Minute
+
+// ErrUserNotFound 按邮箱或手机号找不到用户
+var ErrUserNotFound = errors.New("user not found")
+
+type (
+	ResolveUserIdsReq struct {
+		Emails          []string
+		Mobiles         []string // 非中国大陆的手机号需要带国际区号,如 +12025550123
+		UserIdType      string   // 见UserIdType开头的常量,默认open_id
+		IncludeResigned bool     // 是否包含离职员工
+		NoCache         bool     // 不使用缓存,直接查询
+	}
+	// UserIdResult 一个邮箱或手机号的查询结果,Found为false表示找不到该用户
+	UserIdResult struct {
+		UserId string
+		Found  bool
+	}
+)
+
+// GetUserId 按邮箱获取用户的open_id,找不到时返回ErrUserNotFound
 func (l *LarkU) GetUserId(email string) (userId string, err error) {
 	if email == "" {
-		panic("email is empty")
+		err = errors.New("email is empty")
+		return
+	}
+	results, err := l.ResolveUserIds(&ResolveUserIdsReq{Emails: []string{email}})
+	if err != nil {
+		return
+	}
+	if r := results[email]; r.Found {
+		return r.UserId, nil
+	}
+	err = errors.Wrapf(ErrUserNotFound, "email %s", email)
+	return
+}
+
+// ResolveUserIds 按邮箱和手机号批量获取用户id,返回以邮箱、手机号为key的结果,每个输入都有对应的结果,
+// 找不到的用户Found为false.自动按每次50个分批查询,结果缓存10分钟(找不到的缓存1分钟)
+func (l *LarkU) ResolveUserIds(req *ResolveUserIdsReq) (results map[string]UserIdResult, err error) {
+	userIdType := req.UserIdType
+	if userIdType == "" {
+		userIdType = UserIdTypeOpenId
+	}
+	cacheKey := func(key string) string {
+		return userIdType + "|" + strconv.FormatBool(req.IncludeResigned) + "|" + key
+	}
+	results = make(map[string]UserIdResult, len(req.Emails)+len(req.Mobiles))
+	pending := func(keys []string) (missing []string, err error) {
+		for _, key := range keys {
+			if key == "" {
+				return nil, errors.New("email or mobile is empty")
+			}
+			if _, ok := results[key]; ok {
+				continue
+			}
+			if !req.NoCache {
+				if r, ok := l.userIdCache.Get(cacheKey(key)); ok {
+					results[key] = r
+					continue
+				}
+			}
+			results[key] = UserIdResult{}
+			missing = append(missing, key)
+		}
+		return
+	}
+	emails, err := pending(req.Emails)
+	if err != nil {
+		return nil, err
+	}
+	mobiles, err := pending(req.Mobiles)
+	if err != nil {
+		return nil, err
 	}
 
-	httpCode, respBody, err := l.LarkPost("/open-apis/contact/v3/users/batch_get_id", map[string]interface{}{
-		"emails": []string{email},
-	})
+	for len(emails) > 0 || len(mobiles) > 0 {
+		n, k := len(emails), len(mobiles)
+		if n > maxBatchGetUserId {
+			n = maxBatchGetUserId
+		}
+		if k > maxBatchGetUserId {
+			k = maxBatchGetUserId
+		}
+		found, e := l.batchGetUserId(userIdType, emails[:n], mobiles[:k], req.IncludeResigned)
+		if e != nil {
+			return nil, e
+		}
+		for _, key := range append(emails[:n:n], mobiles[:k]...) {
+			r := UserIdResult{}
+			if userId, ok := found[key]; ok && userId != "" {
+				r = UserIdResult{UserId: userId, Found: true}
+				l.userIdCache.Set(cacheKey(key), r)
+			} else {
+				l.userIdCache.SetWithTTL(cacheKey(key), r, userIdNotFoundTTL)
+			}
+			results[key] = r
+		}
+		emails, mobiles = emails[n:], mobiles[k:]
+	}
+	return
+}
+
+// batchGetUserId 返回邮箱、手机号到用户id的映射,找不到的用户不在其中
+func (l *LarkU) batchGetUserId(userIdType string, emails, mobiles []string, includeResigned bool) (found map[string]string, err error) {
+	param := map[string]interface{}{}
+	if len(emails) > 0 {
+		param["emails"] = emails
+	}
+	if len(mobiles) > 0 {
+		param["mobiles"] = mobiles
+	}
+	if includeResigned {
+		param["include_resigned"] = true
+	}
+	httpCode, respBody, err := l.LarkPost("/open-apis/contact/v3/users/batch_get_id?"+url.Values{"user_id_type": {userIdType}}.Encode(), param)
 	if err != nil {
 		err = errors.Errorf("http error: %+v", err)
 		return
 	}
 	if httpCode != http.StatusOK {
-		err = errors.Errorf("http error: code= %d | %+v", httpCode, respBody)
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
 		return
 	}
-	type GetUserIdResp struct {
+	type BatchGetUserIdResp struct {
 		Code int32  `json:"code,omitempty"`
 		Msg  string `json:"msg"`
 		Data struct {
 			UserList []struct {
 				UserId string `json:"user_id,omitempty"`
 				Email  string `json:"email,omitempty"`
+				Mobile string `json:"mobile,omitempty"`
 			} `json:"user_list,omitempty"`
 		}
 	}
-	m := new(GetUserIdResp)
+	m := new(BatchGetUserIdResp)
 	_ = json.Unmarshal(respBody, &m)
 	if m.Code != 0 {
 		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
 		return
 	}
-	userId = fmt.Sprint(m.Data.UserList[0].UserId)
+	found = make(map[string]string, len(m.Data.UserList))
+	for _, u := range m.Data.UserList {
+		if u.Email != "" {
+			found[u.Email] = u.UserId
+		}
+		if u.Mobile != "" {
+			found[u.Mobile] = u.UserId
+		}
+	}
 	return
 }
+
+/** -------------------------------------------------用户-------------------------------------------------------------------- **/
+
+/** -------------------------------------------------用户信息-------------------------------------------------------------------- **/
+
+// 部门id类型
+const (
+	DepartmentIdTypeDepartmentId     = "department_id"
+	DepartmentIdTypeOpenDepartmentId = "open_department_id"
+)
+
+// 员工类型
+const (
+	EmployeeTypeRegular    = 1 // 正式员工
+	EmployeeTypeIntern     = 2 // 实习生
+	EmployeeTypeOutsourced = 3 // 外包
+	EmployeeTypeContractor = 4 // 劳务
+	EmployeeTypeConsultant = 5 // 顾问
+)
+
+// maxBatchGetUser 批量获取用户信息每次最多50个
+const maxBatchGetUser = 50
+
+type (
+	User struct {
+		UserId          string      `json:"user_id,omitempty"`
+		OpenId          string      `json:"open_id,omitempty"`
+		UnionId         string      `json:"union_id,omitempty"`
+		Name            string      `json:"name,omitempty"`
+		EnName          string      `json:"en_name,omitempty"`
+		Nickname        string      `json:"nickname,omitempty"`
+		Email           string      `json:"email,omitempty"`
+		EnterpriseEmail string      `json:"enterprise_email,omitempty"`
+		Mobile          string      `json:"mobile,omitempty"`
+		Gender          int         `json:"gender,omitempty"` // 0保密 1男 2女 3其他
+		Avatar          *UserAvatar `json:"avatar,omitempty"`
+		Status          *UserStatus `json:"status,omitempty"`
+		DepartmentIds   []string    `json:"department_ids,omitempty"` // 类型由department_id_type决定
+		LeaderUserId    string      `json:"leader_user_id,omitempty"` // 类型由user_id_type决定
+		City            string      `json:"city,omitempty"`
+		Country         string      `json:"country,omitempty"`
+		WorkStation     string      `json:"work_station,omitempty"`
+		JoinTime        int64       `json:"join_time,omitempty"` // 入职时间,秒级时间戳
+		EmployeeNo      string      `json:"employee_no,omitempty"`
+		EmployeeType    int         `json:"employee_type,omitempty"` // 见EmployeeType开头的常量
+		JobTitle        string      `json:"job_title,omitempty"`
+		IsTenantManager bool        `json:"is_tenant_manager,omitempty"`
+	}
+	UserAvatar struct {
+		Avatar72     string `json:"avatar_72,omitempty"`
+		Avatar240    string `json:"avatar_240,omitempty"`
+		Avatar640    string `json:"avatar_640,omitempty"`
+		AvatarOrigin string `json:"avatar_origin,omitempty"`
+	}
+	UserStatus struct {
+		IsFrozen    bool `json:"is_frozen,omitempty"`    // 是否暂停
+		IsResigned  bool `json:"is_resigned,omitempty"`  // 是否离职
+		IsActivated bool `json:"is_activated,omitempty"` // 是否激活
+		IsExited    bool `json:"is_exited,omitempty"`    // 是否主动退出
+		IsUnjoin    bool `json:"is_unjoin,omitempty"`    // 是否未加入
+	}
+)
+
+// DisplayName 优先返回中文名,没有时依次返回英文名、昵称
+func (u *User) DisplayName() string {
+	switch {
+	case u.Name != "":
+		return u.Name
+	case u.EnName != "":
+		return u.EnName
+	}
+	return u.Nickname
+}
+
+// Id 返回userIdType对应的用户id
+func (u *User) Id(userIdType string) string {
+	switch userIdType {
+	case UserIdTypeUserId:
+		return u.UserId
+	case UserIdTypeUnionId:
+		return u.UnionId
+	}
+	return u.OpenId
+}
+
+func contactIdQuery(userIdType, departmentIdType string) url.Values {
+	values := url.Values{}
+	if userIdType != "" {
+		values.Set("user_id_type", userIdType)
+	}
+	if departmentIdType != "" {
+		values.Set("department_id_type", departmentIdType)
+	}
+	return values
+}
+
+// GetUser 获取用户信息,userIdType见UserIdType开头的常量,departmentIdType见DepartmentIdType开头的常量,为空时使用open_id和open_department_id
+func (l *LarkU) GetUser(userId, userIdType, departmentIdType string) (user *User, err error) {
+	httpCode, respBody, err := l.LarkGet("/open-apis/contact/v3/users/"+url.PathEscape(userId), contactIdQuery(userIdType, departmentIdType))
+	if err != nil {
+		err = errors.Errorf("http error: %+v", err)
+		return
+	}
+	if httpCode != http.StatusOK {
+		err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+		return
+	}
+	type GetUserResp struct {
+		Code int32  `json:"code,omitempty"`
+		Msg  string `json:"msg,omitempty"`
+		Data struct {
+			User *User `json:"user,omitempty"`
+		}
+	}
+	m := new(GetUserResp)
+	_ = json.Unmarshal(respBody, &m)
+	if m.Code != 0 {
+		err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+		return
+	}
+	user = m.Data.User
+	return
+}
+
+// BatchGetUsers 批量获取用户信息,自动按每次50个分批查询;不存在或无权限查看的用户不在返回结果中
+func (l *LarkU) BatchGetUsers(userIds []string, userIdType, departmentIdType string) (users []*User, err error) {
+	for start := 0; start < len(userIds); start += maxBatchGetUser {
+		end := start + maxBatchGetUser
+		if end > len(userIds) {
+			end = len(userIds)
+		}
+		values := contactIdQuery(userIdType, departmentIdType)
+		values["user_ids"] = userIds[start:end]
+		httpCode, respBody, e := l.LarkGet("/open-apis/contact/v3/users/batch", values)
+		if e != nil {
+			err = errors.Errorf("http error: %+v", e)
+			return
+		}
+		if httpCode != http.StatusOK {
+			err = errors.Errorf("http error: code= %d | %s", httpCode, string(respBody))
+			return
+		}
+		type BatchGetUsersResp struct {
+			Code int32  `json:"code,omitempty"`
+			Msg  string `json:"msg,omitempty"`
+			Data struct {
+				Items []*User `json:"items,omitempty"`
+			}
+		}
+		m := new(BatchGetUsersResp)
+		_ = json.Unmarshal(respBody, &m)
+		if m.Code != 0 {
+			err = errors.Errorf("remote service error: code = %d | %s", m.Code, m.Msg)
+			return
+		}
+		users = append(users, m.Data.Items...)
+	}
+	return
+}
+
+// UserNames 批量获取用户的显示名称,返回用户id到名称的映射,用于在报表中把id替换成名字
+func (l *LarkU) UserNames(userIds []string, userIdType string) (names map[string]string, err error) {
+	users, err := l.BatchGetUsers(userIds, userIdType, "")
+	if err != nil {
+		return
+	}
+	names = make(map[string]string, len(users))
+	for _, u := range users {
+		names[u.Id(userIdType)] = u.DisplayName()
+	}
+	return
+}
+
+/** -------------------------------------------------用户信息-------------------------------------------------------------------- **/